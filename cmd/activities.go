@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var activitiesWatch bool
+
+var activitiesCmd = &cobra.Command{
+	Use:     "activities",
+	Short:   "List running server activities such as scans and media analysis",
+	GroupID: "media",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		activities, err := plex.ListActivities(ctx, client)
+		if err != nil {
+			slog.Error("SDK: Failed to list activities", "error", err)
+			return err
+		}
+
+		if len(activities) == 0 {
+			if usesRaw, _ := ui.RawFormat(); !usesRaw {
+				fmt.Println("No activities running.")
+				return nil
+			}
+			activities = []operations.Activity{}
+		}
+		if activitiesWatch {
+			return followActivities(ctx, client, nil)
+		}
+
+		var rows [][]string
+		for _, a := range activities {
+			progress := "-"
+			if p, ok := plex.ActivityProgress(a); ok {
+				progress = fmt.Sprintf("%.0f%%", p*100)
+			}
+			cancellable := "false"
+			if ui.PtrToBool(a.Cancellable) {
+				cancellable = "true"
+			}
+			rows = append(rows, []string{
+				ui.PtrToString(a.UUID),
+				ui.PtrToString(a.Type),
+				ui.PtrToString(a.Title),
+				ui.PtrToString(a.Subtitle),
+				progress,
+				cancellable,
+			})
+		}
+
+		return commands.Print(presenters.SimplePresenter{
+			T:       "Activities",
+			H:       []string{"UUID", "TYPE", "TITLE", "SUBTITLE", "PROGRESS", "CANCELLABLE"},
+			R:       rows,
+			RawData: activities,
+		}, opts)
+	}),
+}
+
+var activitiesCancelCmd = &cobra.Command{
+	Use:   "cancel [uuid]",
+	Short: "Cancel a running activity",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		var id string
+		if len(args) > 0 {
+			id = args[0]
+		} else {
+			activities, err := plex.ListActivities(ctx, client)
+			if err != nil {
+				return err
+			}
+			var options []struct{ Title, Desc, Value string }
+			for _, a := range activities {
+				if !ui.PtrToBool(a.Cancellable) {
+					continue
+				}
+				options = append(options, struct{ Title, Desc, Value string }{
+					Title: ui.PtrToString(a.Title),
+					Desc:  ui.PtrToString(a.Subtitle),
+					Value: ui.PtrToString(a.UUID),
+				})
+			}
+			if len(options) == 0 {
+				fmt.Println("No cancellable activities running.")
+				return nil
+			}
+			id, err = ui.SelectOption("Select an activity to cancel", options)
+			if err != nil {
+				return err
+			}
+		}
+
+		slog.Debug("SDK: Cancelling activity", "uuid", id)
+		_, err := client.SDK.Activities.CancelActivity(ctx, operations.CancelActivityRequest{
+			ActivityID: id,
+		})
+		if err != nil {
+			slog.Error("SDK: Cancel activity failed", "uuid", id, "error", err)
+			return err
+		}
+		fmt.Printf("Activity %s cancelled.\n", id)
+		return nil
+	}),
+}
+
+// startAndFollow runs start and, when wait is set, follows the activities it kicked off until they finish
+func startAndFollow(ctx context.Context, client *plex.Client, wait bool, start func() error) error {
+	if !wait {
+		return start()
+	}
+
+	// Snapshot running activities so only the ones started here are followed
+	activities, err := plex.ListActivities(ctx, client)
+	if err != nil {
+		return err
+	}
+	existing := plex.ActivityIDs(activities)
+
+	if err := start(); err != nil {
+		return err
+	}
+	return followActivities(ctx, client, existing)
+}
+
+// followActivities redraws the progress of activities started after existing until they finish.
+// With a nil existing list every running activity is followed.
+func followActivities(ctx context.Context, client *plex.Client, existing []string) error {
+	var lines ui.LiveLines
+	found, err := plex.WaitForActivities(ctx, client, existing, func(running []operations.Activity) {
+		lines.Render(activityLines(running))
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Println("No server activity was reported.")
+		return nil
+	}
+	lines.Render(nil)
+	ui.RenderSuccess("All activities completed.")
+	return nil
+}
+
+func activityLines(activities []operations.Activity) []string {
+	lines := make([]string, 0, len(activities))
+	for _, a := range activities {
+		title := ui.PtrToString(a.Title)
+		if sub := ui.PtrToString(a.Subtitle); sub != "" {
+			title = fmt.Sprintf("%s (%s)", title, sub)
+		}
+		if r := []rune(title); len(r) > 50 {
+			title = string(r[:47]) + "..."
+		}
+		p, known := plex.ActivityProgress(a)
+		lines = append(lines, fmt.Sprintf("%-50s %s", title, ui.ProgressBar(p, known, 30)))
+	}
+	return lines
+}
+
+func init() {
+	rootCmd.AddCommand(activitiesCmd)
+	activitiesCmd.AddCommand(activitiesCancelCmd)
+
+	activitiesCancelCmd.ValidArgsFunction = commands.Complete(true, func(ctx context.Context) ([]commands.Suggestion, error) {
+		client, err := plex.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		activities, err := plex.ListActivities(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		var suggestions []commands.Suggestion
+		for _, a := range activities {
+			if ui.PtrToBool(a.Cancellable) {
+				suggestions = append(suggestions, commands.Suggestion{Value: ui.PtrToString(a.UUID), Desc: ui.PtrToString(a.Title)})
+			}
+		}
+		return suggestions, nil
+	})
+
+	activitiesCmd.Flags().BoolVarP(&activitiesWatch, "watch", "w", false, "Follow progress until all running activities finish")
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/cache"
+	"github.com/ygelfand/plexctl/internal/config"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var (
+	cacheNamespace string
+	cacheMaxSizeMB int
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and maintain the local cache",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size broken down by namespace",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		cm, err := cache.Get(cfg.CacheDir)
+		if err != nil {
+			return err
+		}
+		stats, err := cm.Stats()
+		if err != nil {
+			return fmt.Errorf("failed to read cache: %w", err)
+		}
+
+		var rows [][]string
+		var entries, expired int
+		var size int64
+		for _, s := range stats {
+			rows = append(rows, []string{s.Namespace, fmt.Sprintf("%d", s.Entries), fmt.Sprintf("%d", s.Expired), ui.FormatBytes(s.Size)})
+			entries += s.Entries
+			expired += s.Expired
+			size += s.Size
+		}
+
+		limit := "unlimited"
+		if cfg.CacheMaxSizeMB > 0 {
+			limit = ui.FormatBytes(maxCacheBytes(cfg.CacheMaxSizeMB))
+		}
+		rows = append(rows, []string{"TOTAL", fmt.Sprintf("%d", entries), fmt.Sprintf("%d", expired), fmt.Sprintf("%s / %s", ui.FormatBytes(size), limit)})
+
+		return ui.OutputData{
+			Title:   fmt.Sprintf("CACHE (%s)", cm.Dir()),
+			Headers: []string{"NAMESPACE", "ENTRIES", "EXPIRED", "SIZE"},
+			Rows:    rows,
+			Raw: map[string]any{
+				"dir":        cm.Dir(),
+				"max_size":   maxCacheBytes(cfg.CacheMaxSizeMB),
+				"size":       size,
+				"namespaces": stats,
+			},
+		}.Print()
+	},
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List cache entries, most recently used first",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateNamespaces(cacheNamespace); err != nil {
+			return err
+		}
+		cm, err := cache.Get(config.Get().CacheDir)
+		if err != nil {
+			return err
+		}
+		entries, err := cm.Entries()
+		if err != nil {
+			return fmt.Errorf("failed to read cache: %w", err)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].LastUsed.After(entries[j].LastUsed) })
+
+		now := time.Now()
+		var rows [][]string
+		var raw []cache.Entry
+		for _, e := range entries {
+			if cacheNamespace != "" && e.Namespace != cacheNamespace {
+				continue
+			}
+			expires := "never"
+			if e.Expired(now) {
+				expires = "expired"
+			} else if !e.ExpiresAt.IsZero() {
+				expires = e.ExpiresAt.Format("2006-01-02 15:04")
+			}
+			key := e.Desc
+			if key == "" {
+				key = e.Key
+			}
+			if key == "" {
+				key = e.File
+			}
+			rows = append(rows, []string{key, e.Namespace, e.Context, ui.FormatBytes(e.Size), e.LastUsed.Format("2006-01-02 15:04"), expires})
+			raw = append(raw, e)
+		}
+
+		return ui.OutputData{
+			Title:   "CACHE ENTRIES",
+			Headers: []string{"KEY", "NAMESPACE", "CONTEXT", "SIZE", "LAST USED", "EXPIRES"},
+			Rows:    rows,
+			Raw:     raw,
+		}.Print()
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [namespace...]",
+	Short: "Remove cached data, optionally limited to namespaces",
+	Long: fmt.Sprintf(`Remove cached data. With no arguments the whole cache is cleared, otherwise only
+the given namespaces (%s, %s).`, strings.Join(cache.Namespaces, ", "), cache.NamespaceLegacy),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateNamespaces(args...); err != nil {
+			return err
+		}
+		cm, err := cache.Get(config.Get().CacheDir)
+		if err != nil {
+			return err
+		}
+		count, freed, err := cm.Clear(args...)
+		if err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		ui.RenderSuccess(fmt.Sprintf("Removed %d entries (%s)", count, ui.FormatBytes(freed)))
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove expired entries and evict old ones above the size limit",
+	Long: `Remove expired entries, then evict the least recently used entries until the cache
+is below cache_max_size_mb. This also runs in the background at most once an hour.`,
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		maxSize := cfg.CacheMaxSizeMB
+		if cmd.Flags().Changed("max-size-mb") {
+			maxSize = cacheMaxSizeMB
+		}
+		cm, err := cache.Get(cfg.CacheDir)
+		if err != nil {
+			return err
+		}
+		res, err := cm.Prune(maxCacheBytes(maxSize))
+		if err != nil {
+			return fmt.Errorf("failed to prune cache: %w", err)
+		}
+		ui.RenderSuccess(fmt.Sprintf("Removed %d expired and %d least recently used entries (%s)", res.Expired, res.Evicted, ui.FormatBytes(res.Freed)))
+		return nil
+	},
+}
+
+// cachePruneWait bounds how long a command waits on exit for the background prune to finish
+const cachePruneWait = 2 * time.Second
+
+// cachePruneDone is closed once the prune started by startCachePrune is done
+var cachePruneDone <-chan struct{}
+
+// startCachePrune trims the cache in the background so it does not grow without bound
+func startCachePrune(cmd *cobra.Command) {
+	cfg := config.Get()
+	if cfg.NoCache || cmd.Parent() == cacheCmd {
+		return
+	}
+	cm, err := cache.Get(cfg.CacheDir)
+	if err != nil {
+		return
+	}
+	cachePruneDone = cm.PruneInBackground(maxCacheBytes(cfg.CacheMaxSizeMB))
+}
+
+// waitCachePrune gives the background prune up to cachePruneWait to finish before the process exits.
+// A prune cut short is tried again by the next command.
+func waitCachePrune() {
+	if cachePruneDone == nil {
+		return
+	}
+	select {
+	case <-cachePruneDone:
+	case <-time.After(cachePruneWait):
+		slog.Debug("Cache: Prune still running at exit, leaving it for the next run")
+	}
+}
+
+func maxCacheBytes(mb int) int64 {
+	return int64(mb) * 1024 * 1024
+}
+
+func validateNamespaces(names ...string) error {
+	for _, ns := range names {
+		if ns != "" && ns != cache.NamespaceLegacy && !slices.Contains(cache.Namespaces, ns) {
+			return fmt.Errorf("unknown cache namespace '%s' (allowed: %s, %s)", ns, strings.Join(cache.Namespaces, ", "), cache.NamespaceLegacy)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheLsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	cacheLsCmd.Flags().StringVar(&cacheNamespace, "namespace", "", "Only list entries in this namespace")
+	cachePruneCmd.Flags().IntVar(&cacheMaxSizeMB, "max-size-mb", 0, "Size limit in MB for this run (default from cache_max_size_mb)")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
 )
 
 var collectionCmd = &cobra.Command{
@@ -21,11 +22,15 @@ var collectionCmd = &cobra.Command{
 var collectionListCmd = &cobra.Command{
 	Use:   "list [library_id]",
 	Short: "List collections in a library",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MaximumNArgs(1),
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		libraryID, err := strconv.ParseInt(args[0], 10, 64)
+		sectionID, err := commands.ResolveArg(ctx, client, resolve.Library, args)
 		if err != nil {
-			return fmt.Errorf("invalid library ID: %w", err)
+			return err
+		}
+		libraryID, err := parseLibraryID(sectionID)
+		if err != nil {
+			return err
 		}
 
 		res, err := client.SDK.Library.GetCollections(ctx, operations.GetCollectionsRequest{
@@ -41,7 +46,7 @@ var collectionListCmd = &cobra.Command{
 		}
 
 		return commands.Print(&presenters.CollectionsPresenter{
-			SectionID:   args[0],
+			SectionID:   sectionID,
 			Collections: res.MediaContainerWithMetadata.MediaContainer.Metadata,
 			RawData:     res.MediaContainerWithMetadata.MediaContainer.Metadata,
 		}, opts)
@@ -49,32 +54,32 @@ var collectionListCmd = &cobra.Command{
 }
 
 var collectionShowCmd = &cobra.Command{
-	Use:   "show [collection_id]",
+	Use:   "show [collection_id...]",
 	Short: "Show items in a collection",
-	Args:  cobra.ExactArgs(1),
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		collectionID, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid collection ID: %w", err)
-		}
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			collectionID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid collection ID: %w", err)
+			}
 
-		res, err := client.SDK.Content.GetCollectionItems(ctx, operations.GetCollectionItemsRequest{
-			CollectionID: collectionID,
-		})
-		if err != nil {
-			return err
-		}
+			res, err := client.SDK.Content.GetCollectionItems(ctx, operations.GetCollectionItemsRequest{
+				CollectionID: collectionID,
+			})
+			if err != nil {
+				return err
+			}
 
-		if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil || len(res.MediaContainerWithMetadata.MediaContainer.Metadata) == 0 {
-			fmt.Println("No items found in this collection.")
-			return nil
-		}
+			if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil || len(res.MediaContainerWithMetadata.MediaContainer.Metadata) == 0 {
+				return commands.PrintDone("No items found in this collection.", []any{}, opts)
+			}
 
-		return commands.Print(&presenters.LibraryItemsPresenter{
-			SectionID: fmt.Sprintf("Collection %d", collectionID),
-			Items:     presenters.MapMetadata(res.MediaContainerWithMetadata.MediaContainer.Metadata),
-			RawData:   res.MediaContainerWithMetadata.MediaContainer.Metadata,
-		}, opts)
+			return commands.Print(&presenters.LibraryItemsPresenter{
+				SectionID: fmt.Sprintf("Collection %d", collectionID),
+				Items:     presenters.MapMetadata(res.MediaContainerWithMetadata.MediaContainer.Metadata),
+				RawData:   res.MediaContainerWithMetadata.MediaContainer.Metadata,
+			}, opts)
+		})
 	}),
 }
 
@@ -82,4 +87,6 @@ func init() {
 	rootCmd.AddCommand(collectionCmd)
 	collectionCmd.AddCommand(collectionListCmd)
 	collectionCmd.AddCommand(collectionShowCmd)
+	collectionListCmd.ValidArgsFunction = commands.CompleteKind(resolve.Library, true)
+	commands.AcceptKeys(collectionShowCmd, "ratingKey", resolve.Collection)
 }
@@ -0,0 +1,485 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/config"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var (
+	contextToken  string
+	contextServer string
+	contextTheme  string
+	contextOutput string
+	showSecrets   bool
+)
+
+// settingFlags maps top-level config keys to the root flags that can override them
+var settingFlags = map[string]string{
+	"output":   "output",
+	"verbose":  "verbose",
+	"no_cache": "no-cache",
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage plexctl configuration and contexts",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+}
+
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List all configured contexts",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if len(cfg.Contexts) == 0 {
+			fmt.Println("No contexts configured.")
+			return nil
+		}
+
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var rows [][]string
+		for _, name := range names {
+			ctx := cfg.Contexts[name]
+			current := ""
+			if name == cfg.ActiveContext {
+				current = "*"
+			}
+			server := ctx.DefaultServer
+			if srv, ok := cfg.Servers[ctx.DefaultServer]; ok && srv.Name != "" {
+				server = srv.Name
+			}
+			account := "inherited"
+			if ctx.Token != "" {
+				account = "own token"
+			}
+			rows = append(rows, []string{current, name, account, server, ctx.Theme, ctx.OutputFormat})
+		}
+
+		return ui.OutputData{
+			Title:   "Contexts",
+			Headers: []string{"CURRENT", "NAME", "ACCOUNT", "SERVER", "THEME", "OUTPUT"},
+			Rows:    rows,
+			Raw:     cfg.Contexts,
+		}.Print()
+	},
+}
+
+var configCurrentContextCmd = &cobra.Command{
+	Use:   "current-context",
+	Short: "Print the active context",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if cfg.ActiveContext == "" {
+			return fmt.Errorf("no context is active")
+		}
+		fmt.Println(cfg.ActiveContext)
+		return nil
+	},
+}
+
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context [name]",
+	Short: "Set the context used by default",
+	Args:  cobra.ExactArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		name := args[0]
+		if _, ok := cfg.Contexts[name]; !ok {
+			return fmt.Errorf("context '%s' not found in configuration", name)
+		}
+
+		slog.Debug("Config: Switching current context", "context", name)
+		cfg.CurrentContext = name
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		ui.RenderSuccess(fmt.Sprintf("Switched to context: %s", name))
+		return nil
+	},
+}
+
+var configSetContextCmd = &cobra.Command{
+	Use:   "set-context [name]",
+	Short: "Create or update a context",
+	Long: `Create or update a context. Only the flags provided are changed; settings left
+empty in a context are inherited from the top-level configuration.`,
+	Args: cobra.ExactArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		name := args[0]
+		ctx := cfg.Contexts[name]
+
+		if cmd.Flags().Changed("token") {
+			ctx.Token = contextToken
+			ctx.HomeUser = config.HomeUser{}
+		}
+		if cmd.Flags().Changed("server") {
+			ctx.DefaultServer = ""
+			if contextServer != "" {
+				id, err := cfg.ResolveServerID(contextServer)
+				if err != nil {
+					return err
+				}
+				ctx.DefaultServer = id
+			}
+		}
+		if cmd.Flags().Changed("theme") {
+			ctx.Theme = contextTheme
+		}
+		if cmd.Flags().Changed("output-format") {
+			ctx.OutputFormat = contextOutput
+		}
+
+		if err := cfg.SetContext(name, ctx); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		ui.RenderSuccess(fmt.Sprintf("Context %s saved", name))
+		return nil
+	},
+}
+
+var configDeleteContextCmd = &cobra.Command{
+	Use:   "delete-context [name]",
+	Short: "Remove a context",
+	Args:  cobra.ExactArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if err := cfg.DeleteContext(args[0]); err != nil {
+			return err
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		ui.RenderSuccess(fmt.Sprintf("Context %s deleted", args[0]))
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print the value of a setting",
+	Long: `Print the value of a setting addressed by its dotted key, e.g. theme or
+servers.<ID>.libraries.settings.<ID>.view_mode. Sections are printed as a list of
+their settings.`,
+	Args: cobra.ExactArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		val, err := cfg.GetValue(args[0])
+		if err != nil {
+			return err
+		}
+		kind := reflect.ValueOf(val).Kind()
+		if kind == reflect.Struct || kind == reflect.Map {
+			return printSettings(cmd, cfg, args[0]+".")
+		}
+		if isSecret(args[0]) && !showSecrets {
+			val = redact(fmt.Sprint(val))
+		}
+		fmt.Println(formatSetting(val))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting and save it",
+	Long: `Change a setting and save it to the config file. Lists are given as comma
+separated values. When a context is active, settings it overrides are saved to
+the context and the others to the top level.`,
+	Args: cobra.ExactArgs(2),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		key, value := args[0], args[1]
+
+		switch key {
+		case "theme":
+			if _, ok := ui.FindTheme(value); !ok {
+				return fmt.Errorf("unknown theme '%s'", value)
+			}
+		case "output":
+			if err := ui.ValidateOutputFormat(value); err != nil {
+				return err
+			}
+		case "default_server":
+			id, err := cfg.ResolveServerID(value)
+			if err != nil {
+				return err
+			}
+			value = id
+		}
+
+		if err := cfg.SetValue(key, value); err != nil {
+			return err
+		}
+		slog.Debug("Config: Setting value", "key", key)
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		ui.RenderSuccess(fmt.Sprintf("%s updated", key))
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Short: "Reset a setting to its default",
+	Long: `Reset a setting to its default value. Map entries such as a server or a
+library override are removed entirely.`,
+	Args: cobra.ExactArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if err := cfg.UnsetValue(args[0]); err != nil {
+			return err
+		}
+		slog.Debug("Config: Unsetting value", "key", args[0])
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		ui.RenderSuccess(fmt.Sprintf("%s reset", args[0]))
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List all settings with their type and source",
+	Args:  cobra.MaximumNArgs(1),
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix := ""
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+		return printSettings(cmd, config.Get(), prefix)
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		path := cfg.ConfigPath
+		if path == "" {
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+			path = cfg.ConfigPath
+		}
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+			if runtime.GOOS == "windows" {
+				editor = "notepad"
+			}
+		}
+
+		fields := strings.Fields(editor)
+		c := exec.Command(fields[0], append(fields[1:], path)...)
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+
+		return validateFile(path)
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the config file",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(configFilePath())
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for invalid values",
+	Annotations: map[string]string{
+		ui.AnnotationSkipServerCheck: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := configFilePath()
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("no config file found at %s", path)
+		}
+		return validateFile(path)
+	},
+}
+
+// configFilePath returns the config file in use, even if it failed to load
+func configFilePath() string {
+	if path := config.Get().ConfigPath; path != "" {
+		return path
+	}
+	if cfgFile != "" {
+		return cfgFile
+	}
+	return config.DefaultConfigPath()
+}
+
+// validateFile loads the config file on its own and reports every problem in it
+func validateFile(path string) error {
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	errs := cfg.Validate()
+	if _, ok := ui.FindTheme(cfg.Theme); cfg.Theme != "" && !ok {
+		errs = append(errs, fmt.Errorf("theme: unknown theme '%s'", cfg.Theme))
+	}
+	if cfg.OutputFormat != "" {
+		if err := ui.ValidateOutputFormat(cfg.OutputFormat); err != nil {
+			errs = append(errs, fmt.Errorf("output: %w", err))
+		}
+	}
+	for name, ctx := range cfg.Contexts {
+		if _, ok := ui.FindTheme(ctx.Theme); ctx.Theme != "" && !ok {
+			errs = append(errs, fmt.Errorf("contexts.%s.theme: unknown theme '%s'", name, ctx.Theme))
+		}
+		if ctx.OutputFormat != "" {
+			if err := ui.ValidateOutputFormat(ctx.OutputFormat); err != nil {
+				errs = append(errs, fmt.Errorf("contexts.%s.output: %w", name, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		for _, e := range errs {
+			ui.RenderError(e)
+		}
+		return fmt.Errorf("%s has %d problem(s)", path, len(errs))
+	}
+	ui.RenderSuccess(fmt.Sprintf("%s is valid", path))
+	return nil
+}
+
+func printSettings(cmd *cobra.Command, cfg *config.Config, prefix string) error {
+	var rows [][]string
+	var raw []config.Setting
+	for _, s := range cfg.Settings() {
+		if prefix != "" && s.Key != prefix && !strings.HasPrefix(s.Key, prefix) {
+			continue
+		}
+		if flag, ok := settingFlags[s.Key]; ok && cmd.Root().PersistentFlags().Changed(flag) {
+			s.Source = config.SourceFlag
+		}
+		if isSecret(s.Key) && !showSecrets {
+			s.Value = redact(fmt.Sprint(s.Value))
+		}
+		raw = append(raw, s)
+		rows = append(rows, []string{s.Key, formatSetting(s.Value), s.Type, s.Source})
+	}
+	if len(rows) == 0 {
+		return errors.New("no matching settings")
+	}
+
+	return ui.OutputData{
+		Title:   "Settings",
+		Headers: []string{"KEY", "VALUE", "TYPE", "SOURCE"},
+		Rows:    rows,
+		Raw:     raw,
+	}.Print()
+}
+
+func formatSetting(v any) string {
+	if list, ok := v.([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+func isSecret(key string) bool {
+	return strings.HasSuffix(key, "token")
+}
+
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "********"
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configCurrentContextCmd)
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configSetContextCmd)
+	configCmd.AddCommand(configDeleteContextCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configSetContextCmd.Flags().StringVar(&contextToken, "token", "", "Plex token for this context")
+	configSetContextCmd.Flags().StringVar(&contextServer, "server", "", "Default server name or ID for this context")
+	configSetContextCmd.Flags().StringVar(&contextTheme, "theme", "", "Theme for this context")
+	configSetContextCmd.Flags().StringVar(&contextOutput, "output-format", "", "Default output format for this context")
+	configGetCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print tokens instead of redacting them")
+	configListCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print tokens instead of redacting them")
+}
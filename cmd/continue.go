@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var nextPlay bool
+
+var continueCmd = &cobra.Command{
+	Use:     "continue",
+	Short:   "List items to continue watching",
+	Long:    `List the items in progress followed by the On Deck items, with how far along each one is and the time left.`,
+	GroupID: "media",
+	Args:    cobra.NoArgs,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		items, err := plex.GetContinueWatching(ctx)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			fmt.Println("Nothing to continue watching.")
+			return nil
+		}
+
+		var rows [][]string
+		for i, meta := range presenters.MapMetadata(items) {
+			progress, left := watchProgress(items[i])
+			rows = append(rows, []string{meta.ID, meta.Title, meta.Type, progress, left})
+		}
+
+		return commands.Print(presenters.SimplePresenter{
+			T:       "Continue Watching",
+			H:       []string{"ID", "TITLE", "TYPE", "PROGRESS", "LEFT"},
+			R:       rows,
+			RawData: items,
+		}, opts)
+	}),
+}
+
+var nextCmd = &cobra.Command{
+	Use:   "next [show_id...]",
+	Short: "Show the next episode to watch in a show",
+	Long: `Show the next episode to watch in a show: the episode in progress, otherwise the first
+unwatched episode after the last watched one. Use --play to start playing it.`,
+	GroupID: "media",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(showKey string, opts *commands.PlexCtlOptions) error {
+			show, err := plex.GetMetadata(ctx, showKey, false)
+			if err != nil {
+				return fmt.Errorf("failed to get show: %w", err)
+			}
+			if show.Type != "show" {
+				return fmt.Errorf("item %s is a %s, not a show", showKey, show.Type)
+			}
+
+			next, err := plex.NextEpisode(ctx, client, showKey)
+			if err != nil {
+				return err
+			}
+			if next == nil {
+				// Scripts get null, nothing when several shows are listed together
+				return commands.PrintDone(fmt.Sprintf("%s is fully watched.", show.Title), nil, opts)
+			}
+
+			if nextPlay {
+				// The leaves listing has no stream details, fetch the full item to play it
+				meta, err := plex.GetMetadata(ctx, ui.PtrToString(next.RatingKey), true)
+				if err != nil {
+					return fmt.Errorf("failed to get metadata: %w", err)
+				}
+				return playMetadata(ctx, meta)
+			}
+
+			meta := presenters.MapMetadata([]components.Metadata{*next})[0]
+			progress, left := watchProgress(*next)
+			return commands.Print(presenters.SimplePresenter{
+				T:       fmt.Sprintf("Next in %s", show.Title),
+				H:       []string{"ID", "TITLE", "DURATION", "PROGRESS", "LEFT"},
+				R:       [][]string{{meta.ID, meta.Title, meta.Duration, progress, left}},
+				RawData: next,
+			}, opts)
+		})
+	}),
+}
+
+// watchProgress returns how much of an item was watched as a percentage and the time left
+func watchProgress(meta components.Metadata) (string, string) {
+	duration := ui.PtrToInt(meta.Duration)
+	offset := ui.PtrToInt(meta.ViewOffset)
+	if duration <= 0 {
+		return "", ""
+	}
+	return fmt.Sprintf("%d%%", offset*100/duration), ui.FormatDuration(duration - offset)
+}
+
+func init() {
+	rootCmd.AddCommand(continueCmd)
+	rootCmd.AddCommand(nextCmd)
+
+	nextCmd.Flags().BoolVar(&nextPlay, "play", false, "Play the next episode")
+	nextCmd.Flags().BoolVar(&tctMode, "tct", false, "Use terminal video")
+	nextCmd.Flags().BoolVar(&noResume, "no-resume", false, "Start playback from the beginning")
+	commands.AcceptKeys(nextCmd, "ratingKey", resolve.Show)
+}
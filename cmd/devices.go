@@ -6,6 +6,7 @@ import (
 
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
@@ -64,7 +65,7 @@ var deviceShowCmd = &cobra.Command{
 			}
 		}
 
-		return fmt.Errorf("device %s not found", deviceID)
+		return clierr.New(clierr.NotFound, "device %s not found", deviceID)
 	}),
 }
 
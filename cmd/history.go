@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,9 +13,15 @@ import (
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/timerange"
+	"github.com/ygelfand/plexctl/internal/ui"
 )
 
-var since string
+var (
+	since             string
+	historyStatsBy    string
+	historyStatsSince string
+)
 
 var historyCmd = &cobra.Command{
 	Use:     "history",
@@ -23,57 +30,20 @@ var historyCmd = &cobra.Command{
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
 		slog.Debug("SDK: Fetching history", "since", since)
 
-		var viewedAt *int64
+		var viewed timerange.Range
 		if since != "" {
-			d, err := time.ParseDuration(since)
-			if err != nil {
-				if strings.HasSuffix(since, "d") {
-					val := strings.TrimSuffix(since, "d")
-					var days int
-					fmt.Sscanf(val, "%d", &days)
-					d = time.Duration(days) * 24 * time.Hour
-				} else if strings.HasSuffix(since, "w") {
-					val := strings.TrimSuffix(since, "w")
-					var weeks int
-					fmt.Sscanf(val, "%d", &weeks)
-					d = time.Duration(weeks) * 7 * 24 * time.Hour
-				} else {
-					return fmt.Errorf("invalid duration format (use 1h, 1d, 1w): %w", err)
-				}
+			var err error
+			if viewed, err = timerange.Parse(since); err != nil {
+				return err
 			}
-			ts := time.Now().Add(-d).Unix()
-			viewedAt = &ts
 		}
 
-		userMap := make(map[int64]string)
-		uRes, err := client.SDK.Users.GetUsers(ctx, operations.GetUsersRequest{})
-		if err == nil && uRes.Object != nil && uRes.Object.MediaContainer != nil {
-			for _, u := range uRes.Object.MediaContainer.User {
-				userMap[u.ID] = u.Title
-			}
-		}
-
-		libMap := make(map[string]string)
-		lRes, err := client.SDK.Library.GetSections(ctx)
-		if err == nil && lRes.Object != nil && lRes.Object.MediaContainer != nil {
-			for _, l := range lRes.Object.MediaContainer.Directory {
-				if l.Key != nil && l.Title != nil {
-					libMap[*l.Key] = *l.Title
-				}
-			}
-		}
-
-		deviceMap := make(map[string]string)
-		dRes, err := client.SDK.Plex.GetServerResources(ctx, operations.GetServerResourcesRequest{})
-		if err == nil {
-			for _, d := range dRes.PlexDevices {
-				deviceMap[d.ClientIdentifier] = d.Name
-			}
-		}
+		userMap, libMap, deviceMap := historyLookups(ctx, client)
 
 		res, err := client.SDK.Status.ListPlaybackHistory(ctx, operations.ListPlaybackHistoryRequest{
 			Sort:        []string{"viewedAt:desc"},
-			ViewedAtGte: viewedAt,
+			ViewedAtGte: unixOrNil(viewed.From),
+			ViewedAtLte: unixBeforeOrNil(viewed.To),
 		})
 		if err != nil {
 			return err
@@ -93,7 +63,123 @@ var historyCmd = &cobra.Command{
 	}),
 }
 
+var historyStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Aggregate playback history by user, library, device, day or hour",
+	Long: `Aggregate the playback history over the --since period into plays, distinct items and runtime,
+with a chart of the plays. The server records when each item was played but not how long or how
+far, so runtime is the full duration of each item played and there is no completion rate. Items no
+longer on the server add no runtime.
+
+  plexctl history stats --by user --since 1y
+  plexctl history stats --by hour --since 30d -o json`,
+	Args: cobra.NoArgs,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		if !slices.Contains(presenters.HistoryGroupings, historyStatsBy) {
+			return fmt.Errorf("invalid grouping '%s' (allowed: %s)", historyStatsBy, strings.Join(presenters.HistoryGroupings, ", "))
+		}
+		viewed, err := timerange.Parse(historyStatsSince)
+		if err != nil {
+			return err
+		}
+
+		userMap, libMap, deviceMap := historyLookups(ctx, client)
+
+		entries, err := plex.ListAllPlaybackHistory(ctx, client, operations.ListPlaybackHistoryRequest{
+			Sort:        []string{"viewedAt:desc"},
+			ViewedAtGte: unixOrNil(viewed.From),
+			ViewedAtLte: unixBeforeOrNil(viewed.To),
+		})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("No history found.")
+			return nil
+		}
+		slog.Debug("SDK: Fetched history", "count", len(entries))
+
+		var keys []string
+		seen := make(map[string]bool)
+		for _, e := range entries {
+			if k := ui.PtrToString(e.RatingKey); k != "" && !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+		current, err := plex.GetMetadataItems(ctx, client, keys)
+		if err != nil {
+			return err
+		}
+
+		items := presenters.MapHistoryMetadata(entries, userMap, libMap, deviceMap)
+		stats, total := presenters.NewHistoryStats(historyStatsBy, items, entries, current)
+
+		return commands.Print(&presenters.HistoryStatsPresenter{
+			By:    historyStatsBy,
+			Stats: stats,
+			Total: total,
+		}, opts)
+	}),
+}
+
+// historyLookups maps the user, library and device IDs found in history entries to their names
+func historyLookups(ctx context.Context, client *plex.Client) (map[int64]string, map[string]string, map[string]string) {
+	userMap := make(map[int64]string)
+	uRes, err := client.SDK.Users.GetUsers(ctx, operations.GetUsersRequest{})
+	if err == nil && uRes.Object != nil && uRes.Object.MediaContainer != nil {
+		for _, u := range uRes.Object.MediaContainer.User {
+			userMap[u.ID] = u.Title
+		}
+	}
+
+	libMap := make(map[string]string)
+	lRes, err := client.SDK.Library.GetSections(ctx)
+	if err == nil && lRes.Object != nil && lRes.Object.MediaContainer != nil {
+		for _, l := range lRes.Object.MediaContainer.Directory {
+			if l.Key != nil && l.Title != nil {
+				libMap[*l.Key] = *l.Title
+			}
+		}
+	}
+
+	deviceMap := make(map[string]string)
+	dRes, err := client.SDK.Plex.GetServerResources(ctx, operations.GetServerResourcesRequest{})
+	if err == nil {
+		for _, d := range dRes.PlexDevices {
+			deviceMap[d.ClientIdentifier] = d.Name
+		}
+	}
+
+	return userMap, libMap, deviceMap
+}
+
+func unixOrNil(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	ts := t.Unix()
+	return &ts
+}
+
+// unixBeforeOrNil returns the last second before t, as the end of a range is exclusive and the
+// history filter on it inclusive
+func unixBeforeOrNil(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	ts := t.Unix()
+	if t.Nanosecond() == 0 {
+		ts--
+	}
+	return &ts
+}
+
 func init() {
 	rootCmd.AddCommand(historyCmd)
-	historyCmd.Flags().StringVar(&since, "since", "1w", "Time period to show (e.g. 1h, 1d, 1w)")
+	historyCmd.AddCommand(historyStatsCmd)
+	historyCmd.Flags().StringVar(&since, "since", "1w", "Time period to show (e.g. 1h, 1d, 1w, yesterday) or a range (e.g. 2026-01-01..2026-02-01)")
+
+	historyStatsCmd.Flags().StringVar(&historyStatsBy, "by", "user", "Group plays by user, library, device, day or hour")
+	historyStatsCmd.Flags().StringVar(&historyStatsSince, "since", "30d", "Time period to aggregate (e.g. 30d, 1y) or a range (e.g. 2026-01-01..2026-02-01)")
 }
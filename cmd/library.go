@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/timerange"
 	"github.com/ygelfand/plexctl/internal/ui"
 )
 
@@ -18,6 +25,20 @@ var (
 	libraryCount int
 	libraryPage  int
 	libraryAll   bool
+
+	libraryFilter     plex.LibraryFilter
+	libraryAddedSince string
+	libraryFields     []string
+
+	libraryStatsTop int
+
+	libraryAuditChecks []string
+	libraryAuditMinRes string
+	libraryAuditQuiet  bool
+
+	libraryScanPath  string
+	libraryScanForce bool
+	libraryWait      bool
 )
 
 var libraryCmd = &cobra.Command{
@@ -53,61 +74,389 @@ var libraryListCmd = &cobra.Command{
 var libraryShowCmd = &cobra.Command{
 	Use:   "show [library_id]",
 	Short: "Show items in a library",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show items in a library. Filters and --server-sort are applied by the server, so they can be
+combined with --count and --page. With --all, pages are printed as they arrive unless the
+output format needs every item at once, or --sort orders the items fetched by their columns.
+
+  plexctl library show 1 --genre Comedy --year 1990-1999 --unwatched --server-sort addedAt:desc
+  plexctl library show Movies --unwatched
+  plexctl library show 2 --added-since 7d --fields id,title,added
+  plexctl library show 2 --added-since 2026-01-01..2026-02-01`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		libraryID := args[0]
-		slog.Debug("SDK: Fetching library content", "library_id", libraryID)
+		if err := presenters.ValidateFields(libraryFields); err != nil {
+			return err
+		}
+		libraryID, err := commands.ResolveArg(ctx, client, resolve.Library, args)
+		if err != nil {
+			return err
+		}
 
-		allMetadata, err := plex.WalkContent(ctx, libraryAll, libraryPage, libraryCount, plex.LibraryWalker(client, libraryID))
+		filter := libraryFilter
+		if libraryAddedSince != "" {
+			added, err := timerange.Parse(libraryAddedSince)
+			if err != nil {
+				return err
+			}
+			filter.Added = added
+		}
+		// Sort by title on the server, so pages can be printed as they arrive
+		if filter.Sort == "" {
+			filter.Sort = "titleSort"
+		}
+		query, err := filter.Query(ctx, libraryID)
 		if err != nil {
 			return err
 		}
+		slog.Debug("SDK: Fetching library content", "library_id", libraryID, "query", query.Encode())
 
-		if len(allMetadata) == 0 {
+		found := 0
+		walker := plex.FilteredLibraryWalker(libraryID, query)
+		err = commands.PrintStream(ctx, &presenters.LibraryItemsPresenter{
+			SectionID:    libraryID,
+			Fields:       libraryFields,
+			ServerSorted: true,
+			Pages: func(ctx context.Context, page func([]components.Metadata) error) error {
+				return plex.WalkContentPages(ctx, libraryAll, libraryPage, libraryCount, walker, func(items []components.Metadata) error {
+					found += len(items)
+					slog.Debug("SDK: Found items", "library_id", libraryID, "count", found)
+					return page(items)
+				})
+			},
+		}, opts)
+		if err != nil {
+			return err
+		}
+
+		if found == 0 && ui.TitledFormat(opts.OutputFormat) {
 			fmt.Println("No items found in this library.")
+		}
+		return nil
+	}),
+}
+
+var libraryStatsCmd = &cobra.Command{
+	Use:   "stats [library_id]",
+	Short: "Report codecs, resolutions and storage used by a library",
+	Long: `Walk every item in a library, or in all libraries when none is given, and report totals
+by resolution, video and audio codec, container, dynamic range and bitrate, along with the
+total size and duration and the largest items.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		libraryID, err := resolveOptionalLibrary(ctx, client, args)
+		if err != nil {
+			return err
+		}
+
+		slog.Debug("SDK: Fetching sections")
+		res, err := client.SDK.Library.GetSections(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get sections: %w", err)
+		}
+		var sections []components.LibrarySection
+		if res.Object != nil && res.Object.MediaContainer != nil {
+			for _, d := range res.Object.MediaContainer.Directory {
+				if libraryID == "" || ui.PtrToString(d.Key) == libraryID {
+					sections = append(sections, d)
+				}
+			}
+		}
+		if len(sections) == 0 {
+			if len(args) > 0 {
+				return clierr.New(clierr.NotFound, "library %s not found", args[0])
+			}
+			fmt.Println("No libraries found.")
 			return nil
 		}
 
-		slog.Debug("SDK: Found items", "library_id", libraryID, "count", len(allMetadata))
-		return commands.Print(&presenters.LibraryItemsPresenter{
-			SectionID: libraryID,
-			Items:     presenters.MapMetadata(allMetadata),
-			RawData:   allMetadata,
-		}, opts)
+		stats := presenters.NewLibraryStats()
+		for _, section := range sections {
+			sectionID := ui.PtrToString(section.Key)
+			leafType, ok := plex.LeafTypes[section.Type]
+			if !ok {
+				slog.Debug("Skipping library without media", "library_id", sectionID, "type", section.Type)
+				continue
+			}
+
+			slog.Debug("SDK: Walking library for stats", "library_id", sectionID, "type", leafType)
+			items, err := plex.WalkContent(ctx, true, 1, 0, plex.FilteredLibraryWalker(sectionID, url.Values{"type": {leafType}}))
+			if err != nil {
+				return fmt.Errorf("failed to list library %s: %w", sectionID, err)
+			}
+			stats.Add(ui.PtrToString(section.Title), items)
+		}
+		stats.Finish(libraryStatsTop)
+
+		return commands.Print(&presenters.LibraryStatsPresenter{Stats: stats}, opts)
 	}),
 }
 
-var libraryRefreshCmd = &cobra.Command{
-	Use:   "refresh [library_id]",
-	Short: "Trigger a metadata refresh for a library",
-	Args:  cobra.ExactArgs(1),
+var libraryAuditCmd = &cobra.Command{
+	Use:   "audit [library_id]",
+	Short: "Find duplicates, unmatched items, missing artwork and other quality problems",
+	Long: fmt.Sprintf(`Walk the movie and show libraries, or only the given one, and report problems with
+their items. Every finding carries the rating key of the affected item, use -q to print
+only those keys so they can be passed on to other commands.
+
+Checks: %s`, strings.Join(plex.AuditChecks, ", ")),
+	Args: cobra.MaximumNArgs(1),
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		libraryID, err := strconv.ParseInt(args[0], 10, 64)
+		for _, c := range libraryAuditChecks {
+			if !slices.Contains(plex.AuditChecks, c) {
+				return fmt.Errorf("unknown check '%s' (allowed: %s)", c, strings.Join(plex.AuditChecks, ", "))
+			}
+		}
+		minRes, ok := plex.ParseResolution(libraryAuditMinRes)
+		if !ok {
+			return fmt.Errorf("invalid resolution '%s', expected e.g. 720, 1080 or 4k", libraryAuditMinRes)
+		}
+		libraryID, err := resolveOptionalLibrary(ctx, client, args)
 		if err != nil {
-			return fmt.Errorf("invalid library ID: %w", err)
+			return err
 		}
 
-		res, err := client.SDK.Library.RefreshSection(ctx, operations.RefreshSectionRequest{
-			SectionID: libraryID,
+		slog.Debug("SDK: Fetching sections")
+		res, err := client.SDK.Library.GetSections(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get sections: %w", err)
+		}
+		var sections []plex.AuditSection
+		if res.Object != nil && res.Object.MediaContainer != nil {
+			for _, d := range res.Object.MediaContainer.Directory {
+				if libraryID == "" || ui.PtrToString(d.Key) == libraryID {
+					sections = append(sections, plex.AuditSection{ID: ui.PtrToString(d.Key), Title: ui.PtrToString(d.Title), Type: d.Type})
+				}
+			}
+		}
+		if len(args) > 0 && len(sections) == 0 {
+			return clierr.New(clierr.NotFound, "library %s not found", args[0])
+		}
+
+		findings, err := plex.Audit(ctx, client, sections, plex.AuditOptions{
+			Checks:        libraryAuditChecks,
+			MinResolution: minRes,
 		})
 		if err != nil {
 			return err
 		}
 
-		if res.StatusCode == 200 {
-			ui.RenderSuccess(fmt.Sprintf("Refresh triggered for library %d", libraryID))
+		if libraryAuditQuiet {
+			var seen []string
+			for _, f := range findings {
+				if f.RatingKey != "" && !slices.Contains(seen, f.RatingKey) {
+					seen = append(seen, f.RatingKey)
+					fmt.Println(f.RatingKey)
+				}
+			}
+			return nil
 		}
-		return nil
+		if len(findings) == 0 {
+			if ui.TitledFormat(opts.OutputFormat) {
+				ui.RenderSuccess("No problems found.")
+				return nil
+			}
+			// Scripts get an empty list rather than a message
+			findings = []plex.AuditFinding{}
+		}
+
+		var rows [][]string
+		for _, f := range findings {
+			rows = append(rows, []string{f.Check, f.RatingKey, f.Title, f.Library, f.Detail})
+		}
+		return commands.Print(presenters.SimplePresenter{
+			T:       fmt.Sprintf("Library Audit (%d findings)", len(findings)),
+			H:       []string{"CHECK", "ID", "TITLE", "LIBRARY", "DETAIL"},
+			R:       rows,
+			RawData: findings,
+		}, opts)
 	}),
 }
 
+var libraryRefreshCmd = &cobra.Command{
+	Use:   "refresh [library_id...]",
+	Short: "Trigger a metadata refresh for a library",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			libraryID, err := parseLibraryID(id)
+			if err != nil {
+				return err
+			}
+
+			res, err := client.SDK.Library.RefreshSection(ctx, operations.RefreshSectionRequest{
+				SectionID: libraryID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if res.StatusCode != 200 {
+				return nil
+			}
+			return commands.PrintDone(fmt.Sprintf("Refresh triggered for library %d", libraryID), libraryAction{Key: id, Action: "refresh"}, opts)
+		})
+	}),
+}
+
+var libraryScanCmd = &cobra.Command{
+	Use:   "scan [library_id...]",
+	Short: "Scan a library for new files, optionally limited to one folder",
+	Long: `Scan a library for new and changed files. With --path only the given folder is scanned,
+which is much cheaper than a full scan of a large library. The path is as seen by the server.`,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			libraryID, err := parseLibraryID(id)
+			if err != nil {
+				return err
+			}
+
+			req := operations.RefreshSectionRequest{SectionID: libraryID}
+			if libraryScanPath != "" {
+				req.Path = &libraryScanPath
+			}
+			if libraryScanForce {
+				req.Force = components.BoolIntTrue.ToPointer()
+			}
+
+			return startAndFollow(ctx, client, libraryWait, func() error {
+				slog.Debug("SDK: Scanning library", "library_id", libraryID, "path", libraryScanPath, "force", libraryScanForce)
+				if _, err := client.SDK.Library.RefreshSection(ctx, req); err != nil {
+					slog.Error("SDK: Library scan failed", "library_id", libraryID, "error", err)
+					return fmt.Errorf("failed to scan library: %w", err)
+				}
+				msg := fmt.Sprintf("Scan triggered for library %d", libraryID)
+				if libraryScanPath != "" {
+					msg = fmt.Sprintf("Scan triggered for %s in library %d", libraryScanPath, libraryID)
+				}
+				return commands.PrintDone(msg, libraryAction{Key: id, Action: "scan", Path: libraryScanPath}, opts)
+			})
+		})
+	}),
+}
+
+var libraryAnalyzeCmd = &cobra.Command{
+	Use:   "analyze [library_id...]",
+	Short: "Analyze the media in a library",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			libraryID, err := parseLibraryID(id)
+			if err != nil {
+				return err
+			}
+
+			return startAndFollow(ctx, client, libraryWait, func() error {
+				slog.Debug("SDK: Analyzing library", "library_id", libraryID)
+				if _, err := client.SDK.Library.StartAnalysis(ctx, operations.StartAnalysisRequest{SectionID: libraryID}); err != nil {
+					slog.Error("SDK: Library analysis failed", "library_id", libraryID, "error", err)
+					return fmt.Errorf("failed to analyze library: %w", err)
+				}
+				return commands.PrintDone(fmt.Sprintf("Analysis triggered for library %d", libraryID), libraryAction{Key: id, Action: "analyze"}, opts)
+			})
+		})
+	}),
+}
+
+var libraryEmptyTrashCmd = &cobra.Command{
+	Use:   "empty-trash [library_id...]",
+	Short: "Remove items whose files are missing from a library",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			libraryID, err := parseLibraryID(id)
+			if err != nil {
+				return err
+			}
+
+			return startAndFollow(ctx, client, libraryWait, func() error {
+				slog.Debug("SDK: Emptying library trash", "library_id", libraryID)
+				if _, err := client.SDK.Library.EmptyTrash(ctx, operations.EmptyTrashRequest{SectionID: libraryID}); err != nil {
+					slog.Error("SDK: Empty trash failed", "library_id", libraryID, "error", err)
+					return fmt.Errorf("failed to empty trash: %w", err)
+				}
+				plex.InvalidateSection(strconv.FormatInt(libraryID, 10))
+				return commands.PrintDone(fmt.Sprintf("Emptying trash for library %d", libraryID), libraryAction{Key: id, Action: "empty-trash"}, opts)
+			})
+		})
+	}),
+}
+
+// libraryAction is printed for each library an action was started for, for scripts
+type libraryAction struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+	Path   string `json:"path,omitempty"`
+}
+
+var libraryCleanBundlesCmd = &cobra.Command{
+	Use:   "clean-bundles",
+	Short: "Remove unused metadata bundles from the server",
+	Args:  cobra.NoArgs,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return startAndFollow(ctx, client, libraryWait, func() error {
+			slog.Debug("SDK: Cleaning bundles")
+			if _, err := client.SDK.Library.CleanBundles(ctx); err != nil {
+				slog.Error("SDK: Clean bundles failed", "error", err)
+				return fmt.Errorf("failed to clean bundles: %w", err)
+			}
+			ui.RenderSuccess("Bundle cleanup triggered")
+			return nil
+		})
+	}),
+}
+
+// resolveOptionalLibrary returns the ID of the library named by the only argument, or "" without one
+func resolveOptionalLibrary(ctx context.Context, client *plex.Client, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	return commands.Resolve(ctx, client, resolve.Library, args[0])
+}
+
+func parseLibraryID(arg string) (int64, error) {
+	libraryID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid library ID: %w", err)
+	}
+	return libraryID, nil
+}
+
 func init() {
 	rootCmd.AddCommand(libraryCmd)
 	libraryCmd.AddCommand(libraryListCmd)
 	libraryCmd.AddCommand(libraryShowCmd)
 	libraryCmd.AddCommand(libraryRefreshCmd)
+	libraryCmd.AddCommand(libraryStatsCmd)
+	libraryCmd.AddCommand(libraryAuditCmd)
+	libraryCmd.AddCommand(libraryScanCmd)
+	libraryCmd.AddCommand(libraryAnalyzeCmd)
+	libraryCmd.AddCommand(libraryEmptyTrashCmd)
+	libraryCmd.AddCommand(libraryCleanBundlesCmd)
 
 	libraryShowCmd.Flags().IntVar(&libraryCount, "count", 50, "Number of items to return per page")
 	libraryShowCmd.Flags().IntVar(&libraryPage, "page", 1, "Page number to return")
 	libraryShowCmd.Flags().BoolVar(&libraryAll, "all", false, "Return all items (overrides count/page)")
+	libraryShowCmd.Flags().StringSliceVar(&libraryFilter.Genres, "genre", nil, "Only items with this genre (name or ID), repeatable")
+	libraryShowCmd.Flags().StringSliceVar(&libraryFilter.Actors, "actor", nil, "Only items with this actor (name or ID), repeatable")
+	libraryShowCmd.Flags().StringSliceVar(&libraryFilter.Labels, "label", nil, "Only items with this label (name or ID), repeatable")
+	libraryShowCmd.Flags().StringVar(&libraryFilter.Year, "year", "", "Only items from this year or range (e.g. 2010-2019)")
+	libraryShowCmd.Flags().BoolVar(&libraryFilter.Unwatched, "unwatched", false, "Only unwatched items")
+	libraryShowCmd.Flags().StringVar(&libraryFilter.Resolution, "resolution", "", fmt.Sprintf("Only items in this resolution (%s)", strings.Join(plex.Resolutions, ", ")))
+	libraryShowCmd.Flags().StringVar(&libraryAddedSince, "added-since", "", "Only items added since a time (e.g. 7d, 2026-01-02, yesterday) or within a range (e.g. 2026-01-01..2026-02-01)")
+	libraryShowCmd.Flags().StringVar(&libraryFilter.Sort, "server-sort", "", "Sort on the server, e.g. addedAt:desc or year,titleSort")
+	libraryShowCmd.Flags().StringSliceVar(&libraryFields, "fields", nil, fmt.Sprintf("Columns to show (%s)", strings.Join(presenters.MetadataFields, ", ")))
+
+	libraryStatsCmd.Flags().IntVar(&libraryStatsTop, "top", 10, "Number of largest items to list")
+	libraryAuditCmd.Flags().StringSliceVar(&libraryAuditChecks, "check", nil, "Only run these checks (default all)")
+	libraryAuditCmd.Flags().StringVar(&libraryAuditMinRes, "min-resolution", "720", "Report files below this resolution (e.g. 720, 1080, 4k)")
+	libraryAuditCmd.Flags().BoolVarP(&libraryAuditQuiet, "quiet", "q", false, "Only print the rating keys of affected items")
+	libraryScanCmd.Flags().StringVar(&libraryScanPath, "path", "", "Only scan this folder (path on the server)")
+	libraryScanCmd.Flags().BoolVar(&libraryScanForce, "force", false, "Force a metadata refresh of the scanned items")
+	for _, c := range []*cobra.Command{libraryScanCmd, libraryAnalyzeCmd, libraryEmptyTrashCmd, libraryCleanBundlesCmd} {
+		c.Flags().BoolVar(&libraryWait, "wait", false, "Wait for the started activity to finish, showing its progress")
+	}
+	libraryShowCmd.ValidArgsFunction = commands.CompleteKind(resolve.Library, true)
+	libraryStatsCmd.ValidArgsFunction = commands.CompleteKind(resolve.Library, true)
+	libraryAuditCmd.ValidArgsFunction = commands.CompleteKind(resolve.Library, true)
+	commands.AcceptKeys(libraryRefreshCmd, "key", resolve.Library)
+	commands.AcceptKeys(libraryScanCmd, "key", resolve.Library)
+	commands.AcceptKeys(libraryAnalyzeCmd, "key", resolve.Library)
+	commands.AcceptKeys(libraryEmptyTrashCmd, "key", resolve.Library)
 }
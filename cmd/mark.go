@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/resolve"
+)
+
+// libraryIdentifier is the media provider of items in the server's libraries
+const libraryIdentifier = "com.plexapp.plugins.library"
+
+var markCmd = &cobra.Command{
+	Use:   "mark",
+	Short: "Mark items as watched or unwatched",
+	Long: `Mark items as watched or unwatched. IDs can also be piped in, one per line or as NDJSON:
+
+  plexctl library show 2 --all --filter 'title~pilot' -o ndjson | plexctl mark watched -`,
+	GroupID: "media",
+}
+
+var markWatchedCmd = &cobra.Command{
+	Use:   "watched [media_id...]",
+	Short: "Mark items as watched",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(ratingKey string, opts *commands.PlexCtlOptions) error {
+			slog.Debug("SDK: Marking watched", "rating_key", ratingKey)
+			if _, err := client.SDK.Timeline.MarkPlayed(ctx, operations.MarkPlayedRequest{
+				Identifier: libraryIdentifier,
+				Key:        &ratingKey,
+			}); err != nil {
+				return fmt.Errorf("failed to mark watched: %w", err)
+			}
+			invalidateMarked(ratingKey)
+			return commands.PrintDone(fmt.Sprintf("Marked %s as watched", ratingKey), markResult{RatingKey: ratingKey, Watched: true}, opts)
+		})
+	}),
+}
+
+var markUnwatchedCmd = &cobra.Command{
+	Use:   "unwatched [media_id...]",
+	Short: "Mark items as unwatched",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(ratingKey string, opts *commands.PlexCtlOptions) error {
+			slog.Debug("SDK: Marking unwatched", "rating_key", ratingKey)
+			if _, err := client.SDK.Timeline.Unscrobble(ctx, operations.UnscrobbleRequest{
+				Identifier: libraryIdentifier,
+				Key:        &ratingKey,
+			}); err != nil {
+				return fmt.Errorf("failed to mark unwatched: %w", err)
+			}
+			invalidateMarked(ratingKey)
+			return commands.PrintDone(fmt.Sprintf("Marked %s as unwatched", ratingKey), markResult{RatingKey: ratingKey, Watched: false}, opts)
+		})
+	}),
+}
+
+// markResult is printed for each item marked, for scripts
+type markResult struct {
+	RatingKey string `json:"ratingKey"`
+	Watched   bool   `json:"watched"`
+}
+
+// invalidateMarked drops the cached watch state of an item, its parents and its library
+func invalidateMarked(ratingKey string) {
+	if _, sectionID := plex.InvalidateItem(ratingKey); sectionID != "" {
+		plex.InvalidateSection(sectionID)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(markCmd)
+	markCmd.AddCommand(markWatchedCmd)
+	markCmd.AddCommand(markUnwatchedCmd)
+
+	commands.AcceptKeys(markWatchedCmd, "ratingKey", resolve.Item)
+	commands.AcceptKeys(markUnwatchedCmd, "ratingKey", resolve.Item)
+}
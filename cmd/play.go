@@ -6,10 +6,12 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/LukeHagar/plexgo/models/components"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/resolve"
 	"github.com/ygelfand/plexctl/internal/tui/player"
 )
 
@@ -20,71 +22,72 @@ var (
 )
 
 var playCmd = &cobra.Command{
-	Use:     "play [media_id]",
+	Use:     "play [media_id...]",
 	Short:   "Play a media item",
-	Args:    cobra.ExactArgs(1),
 	GroupID: "media",
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		mediaID := args[0]
-		slog.Debug("CLI Play: Fetching metadata", "mediaID", mediaID)
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(mediaID string, opts *commands.PlexCtlOptions) error {
+			slog.Debug("CLI Play: Fetching metadata", "mediaID", mediaID)
 
-		meta, err := plex.GetMetadata(ctx, mediaID, true)
-		if err != nil {
-			return fmt.Errorf("failed to get metadata: %w", err)
-		}
-
-		var playFunc tea.Cmd
-
-		if trailer {
-			slog.Debug("CLI Play: Resolving trailer", "mediaID", mediaID)
-			playFunc = player.FetchAndPlayTrailer(meta, tctMode)
-		} else {
-			offset := int64(0)
-			if !noResume && meta.ViewOffset != nil {
-				offset = int64(*meta.ViewOffset)
+			meta, err := plex.GetMetadata(ctx, mediaID, true)
+			if err != nil {
+				return fmt.Errorf("failed to get metadata: %w", err)
 			}
 
-			if offset > 0 {
-				slog.Info("Resuming", "title", meta.Title, "type", meta.Type, "offset", offset)
-			} else {
-				slog.Info("Playing", "title", meta.Title, "type", meta.Type)
+			if trailer {
+				slog.Debug("CLI Play: Resolving trailer", "mediaID", mediaID)
+				return playAndWait(ctx, player.FetchAndPlayTrailer(meta, tctMode), meta.Title+"(Trailer)")
 			}
+			return playMetadata(ctx, meta)
+		})
+	}),
+}
 
-			playFunc = player.PlayMedia(meta, false, tctMode, offset)
-		}
+// playMetadata plays an item, resuming it unless --no-resume was given
+func playMetadata(ctx context.Context, meta *components.Metadata) error {
+	offset := int64(0)
+	if !noResume && meta.ViewOffset != nil {
+		offset = int64(*meta.ViewOffset)
+	}
 
-		if playFunc == nil {
-			return fmt.Errorf("failed to initiate playback")
-		}
+	if offset > 0 {
+		slog.Info("Resuming", "title", meta.Title, "type", meta.Type, "offset", offset)
+	} else {
+		slog.Info("Playing", "title", meta.Title, "type", meta.Type)
+	}
 
-		// Execute the playback function
-		msg := playFunc()
-		if err, ok := msg.(error); ok {
-			return err
-		}
-		title := meta.Title
-		if trailer {
-			title = title + "(Trailer)"
-		}
-		pm := player.GetPlayerManager()
-		if tctMode {
-			fmt.Printf("Playing %s in TCT mode. Press 'q' in mpv or Ctrl+C to stop.\n", title)
-		} else {
-			fmt.Printf("Playing %s . Press Ctrl+C to stop.\n", title)
-		}
+	return playAndWait(ctx, player.PlayMedia(meta, false, tctMode, offset), meta.Title)
+}
 
-		// Wait loop for CLI to keep progress reporting alive
-		for pm.VerifyConnection() {
-			select {
-			case <-ctx.Done():
-				return nil
-			case <-time.After(500 * time.Millisecond):
-				// Just keep waiting
-			}
+// playAndWait starts playback and blocks while the player runs so progress keeps being reported
+func playAndWait(ctx context.Context, playFunc tea.Cmd, title string) error {
+	if playFunc == nil {
+		return fmt.Errorf("failed to initiate playback")
+	}
+
+	// Execute the playback function
+	msg := playFunc()
+	if err, ok := msg.(error); ok {
+		return err
+	}
+	pm := player.GetPlayerManager()
+	if tctMode {
+		fmt.Printf("Playing %s in TCT mode. Press 'q' in mpv or Ctrl+C to stop.\n", title)
+	} else {
+		fmt.Printf("Playing %s . Press Ctrl+C to stop.\n", title)
+	}
+
+	// Wait loop for CLI to keep progress reporting alive
+	for pm.VerifyConnection() {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(500 * time.Millisecond):
+			// Just keep waiting
 		}
+	}
 
-		return nil
-	}),
+	return nil
 }
 
 func init() {
@@ -92,4 +95,5 @@ func init() {
 	playCmd.Flags().BoolVar(&tctMode, "tct", false, "Use terminal video")
 	playCmd.Flags().BoolVar(&noResume, "no-resume", false, "Start playback from the beginning")
 	playCmd.Flags().BoolVar(&trailer, "trailer", false, "Play the primary trailer instead of the full media")
+	commands.AcceptKeys(playCmd, "ratingKey", resolve.Item)
 }
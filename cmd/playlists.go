@@ -10,6 +10,7 @@ import (
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
 )
 
 var playlistCmd = &cobra.Command{
@@ -41,32 +42,32 @@ var playlistListCmd = &cobra.Command{
 }
 
 var playlistShowCmd = &cobra.Command{
-	Use:   "show [playlist_id]",
+	Use:   "show [playlist_id...]",
 	Short: "Show items in a playlist",
-	Args:  cobra.ExactArgs(1),
 	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
-		playlistID, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil {
-			return fmt.Errorf("invalid playlist ID: %w", err)
-		}
+		return commands.ForEachKey(ctx, client, cmd, args, opts, func(id string, opts *commands.PlexCtlOptions) error {
+			playlistID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid playlist ID: %w", err)
+			}
 
-		res, err := client.SDK.Playlist.GetPlaylistItems(ctx, operations.GetPlaylistItemsRequest{
-			PlaylistID: playlistID,
-		})
-		if err != nil {
-			return err
-		}
+			res, err := client.SDK.Playlist.GetPlaylistItems(ctx, operations.GetPlaylistItemsRequest{
+				PlaylistID: playlistID,
+			})
+			if err != nil {
+				return err
+			}
 
-		if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil || len(res.MediaContainerWithMetadata.MediaContainer.Metadata) == 0 {
-			fmt.Println("No items found in this playlist.")
-			return nil
-		}
+			if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil || len(res.MediaContainerWithMetadata.MediaContainer.Metadata) == 0 {
+				return commands.PrintDone("No items found in this playlist.", []any{}, opts)
+			}
 
-		return commands.Print(&presenters.LibraryItemsPresenter{
-			SectionID: fmt.Sprintf("Playlist %d", playlistID),
-			Items:     presenters.MapMetadata(res.MediaContainerWithMetadata.MediaContainer.Metadata),
-			RawData:   res.MediaContainerWithMetadata.MediaContainer.Metadata,
-		}, opts)
+			return commands.Print(&presenters.LibraryItemsPresenter{
+				SectionID: fmt.Sprintf("Playlist %d", playlistID),
+				Items:     presenters.MapMetadata(res.MediaContainerWithMetadata.MediaContainer.Metadata),
+				RawData:   res.MediaContainerWithMetadata.MediaContainer.Metadata,
+			}, opts)
+		})
 	}),
 }
 
@@ -74,4 +75,5 @@ func init() {
 	rootCmd.AddCommand(playlistCmd)
 	playlistCmd.AddCommand(playlistListCmd)
 	playlistCmd.AddCommand(playlistShowCmd)
+	commands.AcceptKeys(playlistShowCmd, "ratingKey", resolve.Playlist)
 }
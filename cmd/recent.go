@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/timerange"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var (
+	recentLibrary string
+	recentSince   string
+	recentType    string
+	recentFormat  string
+	recentTitle   string
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently added items",
+	Long: fmt.Sprintf(`List the items added to every library, or only --library, since the given time or within a
+range such as 2026-01-01..2026-02-01, newest first and grouped by show or artist. By default
+episodes, movies, tracks and photos are listed, use --type to list other kinds of items such
+as seasons or albums.
+
+--format writes a digest instead of a table (%s), for example from a cron job:
+
+  plexctl recent --since 1w --format rss > /var/www/plex.xml`, strings.Join(presenters.FeedFormats, ", ")),
+	GroupID: "media",
+	Args:    cobra.NoArgs,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		added, err := timerange.Parse(recentSince)
+		if err != nil {
+			return err
+		}
+		if recentFormat != "" && !slices.Contains(presenters.FeedFormats, strings.ToLower(recentFormat)) {
+			return fmt.Errorf("invalid format '%s' (allowed: %s)", recentFormat, strings.Join(presenters.FeedFormats, ", "))
+		}
+		if _, ok := plex.MetadataTypes[recentType]; recentType != "" && !ok {
+			var types []string
+			for t := range plex.MetadataTypes {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+			return fmt.Errorf("invalid type '%s' (allowed: %s)", recentType, strings.Join(types, ", "))
+		}
+
+		libraryID := ""
+		if recentLibrary != "" {
+			if libraryID, err = commands.Resolve(ctx, client, resolve.Library, recentLibrary); err != nil {
+				return err
+			}
+		}
+
+		slog.Debug("SDK: Fetching sections")
+		res, err := client.SDK.Library.GetSections(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get sections: %w", err)
+		}
+		var sections []components.LibrarySection
+		if res.Object != nil && res.Object.MediaContainer != nil {
+			for _, d := range res.Object.MediaContainer.Directory {
+				if libraryID == "" || ui.PtrToString(d.Key) == libraryID {
+					sections = append(sections, d)
+				}
+			}
+		}
+		if len(sections) == 0 && libraryID != "" {
+			return clierr.New(clierr.NotFound, "library %s not found", recentLibrary)
+		}
+
+		var groups []presenters.RecentGroup
+		for _, section := range sections {
+			sectionID := ui.PtrToString(section.Key)
+			itemType, ok := plex.LeafTypes[section.Type]
+			if recentType != "" {
+				t := plex.MetadataTypes[recentType]
+				itemType, ok = t.Number, t.Section == section.Type
+			}
+			if !ok {
+				slog.Debug("Skipping library", "library_id", sectionID, "type", section.Type)
+				continue
+			}
+
+			query := url.Values{
+				"type": {itemType},
+				"sort": {"addedAt:desc"},
+			}
+			if !added.From.IsZero() {
+				query.Set("addedAt>>", strconv.FormatInt(added.From.Unix(), 10))
+			}
+			if !added.To.IsZero() {
+				query.Set("addedAt<<", strconv.FormatInt(added.To.Unix(), 10))
+			}
+			slog.Debug("SDK: Fetching recently added", "library_id", sectionID, "type", itemType)
+			items, err := plex.WalkContent(ctx, true, 1, 0, plex.FilteredLibraryWalker(sectionID, query))
+			if err != nil {
+				return fmt.Errorf("failed to list library %s: %w", sectionID, err)
+			}
+			groups = append(groups, presenters.GroupRecent(ui.PtrToString(section.Title), items)...)
+		}
+
+		sort.SliceStable(groups, func(i, j int) bool { return groups[i].AddedAt.After(groups[j].AddedAt) })
+		for i := range groups {
+			groups[i].Link = plex.WebLink(groups[i].ID)
+		}
+
+		if recentFormat != "" {
+			return presenters.RecentFeed{
+				Title:  recentTitle,
+				Link:   "https://app.plex.tv/desktop",
+				Added:  added,
+				Groups: groups,
+			}.Write(os.Stdout, recentFormat)
+		}
+
+		if len(groups) == 0 {
+			fmt.Printf("Nothing added %s.\n", presenters.DescribeAdded(added))
+			return nil
+		}
+		return commands.Print(&presenters.RecentPresenter{Groups: groups}, opts)
+	}),
+}
+
+func init() {
+	rootCmd.AddCommand(recentCmd)
+	recentCmd.Flags().StringVar(&recentLibrary, "library", "", "Only list items from this library, by ID or name")
+	recentCmd.Flags().StringVar(&recentSince, "since", "24h", "List items added since a time (e.g. 24h, 7d, 1mo, yesterday) or within a range (e.g. 2026-01-01..2026-02-01)")
+	recentCmd.Flags().StringVar(&recentType, "type", "", "Type of items to list (movie, show, season, episode, artist, album, track, photo)")
+	recentCmd.Flags().StringVar(&recentFormat, "format", "", "Write a digest instead of a table (markdown, html, rss)")
+	recentCmd.Flags().StringVar(&recentTitle, "title", "New on Plex", "Title of the digest")
+}
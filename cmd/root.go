@@ -3,16 +3,21 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/config"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/tui/widget/poster"
 	"github.com/ygelfand/plexctl/internal/ui"
 )
 
 var (
 	cfgFile    string
+	cfgContext string
 	verbosity  int
 	sortCol    string
 	noCache    bool
@@ -25,13 +30,18 @@ var rootCmd = &cobra.Command{
 	Version:       config.Version,
 	Long:          `plexctl is a comprehensive command-line interface for interacting with Plex Media Server`,
 	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.NoArgs,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		startCachePrune(cmd)
+
 		// Skip check if annotation is present
 		if cmd.Annotations[ui.AnnotationSkipServerCheck] == "true" {
 			return nil
 		}
-		// Skip for built-in help and completion
-		if cmd.Name() == "help" || cmd.Name() == "completion" {
+		// Skip for built-in help and completion, including the requests shells make while completing
+		switch cmd.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
 			return nil
 		}
 		return commands.EnsureActiveServer(cmd.Context())
@@ -45,10 +55,39 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// Execute runs the command line and exits with the code of the kind of error it failed with, if any
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		ui.RenderError(err)
-		os.Exit(1)
+	markUsageErrors(rootCmd)
+	cmd, err := rootCmd.ExecuteC()
+	waitCachePrune()
+	if err == nil {
+		return
+	}
+	ui.RenderError(err)
+	if clierr.KindOf(err) == clierr.Usage && !ui.JSONErrors() {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	os.Exit(clierr.ExitCode(err))
+}
+
+// exit reports an error met before any command runs and exits with its code
+func exit(err error) {
+	ui.RenderError(err)
+	os.Exit(clierr.ExitCode(err))
+}
+
+// markUsageErrors marks the errors of flags and argument checks as clierr.Usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return clierr.Wrap(clierr.Usage, err)
+	})
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return clierr.Wrap(clierr.Usage, validate(cmd, args))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
 	}
 }
 
@@ -59,6 +98,7 @@ func GetRootCmd() *cobra.Command {
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("plexctl version {{.Version}} (commit: %s, date: %s)\n", config.GitCommit, config.BuildDate))
 	cobra.OnInitialize(initConfig)
+	resolve.SetPosterRenderer(poster.Render)
 
 	// Define focused command groups
 	rootCmd.AddGroup(&cobra.Group{ID: "tui", Title: "Interactive"})
@@ -66,15 +106,25 @@ func init() {
 	rootCmd.AddGroup(&cobra.Group{ID: "auth", Title: "Authentication"})
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.plexctl.yaml)")
-	rootCmd.PersistentFlags().StringVarP(&outputType, "output", "o", "table", "Output format (table, json, json-pretty, yaml, csv, txt)")
+	rootCmd.PersistentFlags().StringVar(&cfgContext, "context", "", "config context to use (overrides current_context and PLEXCTL_CONTEXT)")
+	rootCmd.PersistentFlags().StringVarP(&outputType, "output", "o", "table", fmt.Sprintf("Output format (%s)", strings.Join(ui.OutputFormats(), ", ")))
 	rootCmd.PersistentFlags().CountP("verbose", "v", "increase verbosity")
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 
 	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Disable caching")
 	viper.BindPFlag("no_cache", rootCmd.PersistentFlags().Lookup("no-cache"))
 
-	rootCmd.PersistentFlags().StringVar(&sortCol, "sort", "", "column to sort by")
+	rootCmd.PersistentFlags().StringVar(&sortCol, "sort", "", "columns to sort by, e.g. year:desc,title")
 	viper.BindPFlag("sort", rootCmd.PersistentFlags().Lookup("sort"))
+
+	rootCmd.PersistentFlags().StringArray("filter", nil, "only rows where a column equals (=), differs from (!=) or contains (~) a value, e.g. year=2020, repeatable")
+	viper.BindPFlag("filter", rootCmd.PersistentFlags().Lookup("filter"))
+
+	rootCmd.PersistentFlags().Int("limit", 0, "print at most this many rows")
+	viper.BindPFlag("limit", rootCmd.PersistentFlags().Lookup("limit"))
+
+	rootCmd.PersistentFlags().Bool("first", false, "when a title or name matches several items, use the best match instead of asking")
+	viper.BindPFlag("first", rootCmd.PersistentFlags().Lookup("first"))
 }
 
 func initConfig() {
@@ -87,8 +137,7 @@ func initConfig() {
 	} else {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			ui.RenderError(fmt.Errorf("failed to get home directory: %w", err))
-			os.Exit(1)
+			exit(fmt.Errorf("failed to get home directory: %w", err))
 		}
 
 		viper.AddConfigPath(home)
@@ -102,6 +151,7 @@ func initConfig() {
 
 	// Explicitly bind env vars that don't have corresponding flags
 	viper.BindEnv("token")
+	viper.BindEnv("context")
 
 	if err := viper.ReadInConfig(); err == nil {
 		cfg.ConfigPath = viper.ConfigFileUsed()
@@ -109,25 +159,43 @@ func initConfig() {
 
 	// Unmarshal the loaded config into our struct
 	if err := viper.Unmarshal(cfg); err != nil {
-		ui.RenderError(fmt.Errorf("failed to parse config: %w", err))
-		os.Exit(1)
+		exit(fmt.Errorf("failed to parse config: %w", err))
+	}
+
+	// Values from flags and the environment are not written back when the config is saved
+	var flagKeys []string
+	for flag, key := range map[string]string{"no-cache": "no_cache", "verbose": "verbose"} {
+		if rootCmd.PersistentFlags().Changed(flag) {
+			flagKeys = append(flagKeys, key)
+		}
+	}
+	cfg.MarkOverrides(flagKeys...)
+
+	// Apply the selected context: flag, then PLEXCTL_CONTEXT, then current_context
+	contextName := cfgContext
+	if contextName == "" {
+		contextName = viper.GetString("context")
+	}
+	if contextName == "" {
+		contextName = cfg.CurrentContext
+	}
+	if err := cfg.UseContext(contextName); err != nil {
+		exit(clierr.Wrap(clierr.Usage, err))
 	}
 
 	// Ensure flags override config
 	if outputType != "" && outputType != "table" {
-		cfg.OutputFormat = outputType
+		cfg.Override("output", outputType)
 	}
 	if cfg.OutputFormat == "" {
 		cfg.OutputFormat = "table"
 	}
 
-	// Basic validation for output format
-	validFormats := map[string]bool{
-		"table": true, "json": true, "json-pretty": true, "yaml": true, "csv": true, "txt": true, "text": true,
-	}
-	if !validFormats[cfg.OutputFormat] {
-		ui.RenderError(fmt.Errorf("invalid output format: %s", cfg.OutputFormat))
-		os.Exit(1)
+	// Validate the output format, including templates and paths, before any request is made
+	if err := ui.ValidateOutputFormat(cfg.OutputFormat); err != nil {
+		// The format is unusable, so the error is reported as for the default table
+		cfg.OutputFormat = "table"
+		exit(clierr.Wrap(clierr.Usage, err))
 	}
 
 	if verbosity > 0 {
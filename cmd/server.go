@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/spf13/cobra"
@@ -107,4 +109,13 @@ func init() {
 	serverCmd.AddCommand(serverListCmd)
 	serverCmd.AddCommand(serverDiscoverCmd)
 	serverCmd.AddCommand(serverUseCmd)
+
+	serverUseCmd.ValidArgsFunction = commands.Complete(true, func(ctx context.Context) ([]commands.Suggestion, error) {
+		var suggestions []commands.Suggestion
+		for id, srv := range config.Get().Servers {
+			suggestions = append(suggestions, commands.Suggestion{Value: id, Desc: srv.Name, Name: srv.Name})
+		}
+		slices.SortFunc(suggestions, func(a, b commands.Suggestion) int { return strings.Compare(a.Desc, b.Desc) })
+		return suggestions, nil
+	})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/commands"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
@@ -159,7 +160,7 @@ var sessionShowCmd = &cobra.Command{
 
 		}
 
-		return fmt.Errorf("session %s not found", sessionID)
+		return clierr.New(clierr.NotFound, "session %s not found", sessionID)
 	}),
 }
 
@@ -222,7 +223,7 @@ var sessionStopCmd = &cobra.Command{
 		}
 
 		if tRes.StatusCode != 200 {
-			return fmt.Errorf("failed to terminate session: %d", tRes.StatusCode)
+			return clierr.FromStatus(tRes.StatusCode, "failed to terminate session: %d", tRes.StatusCode)
 		}
 
 		fmt.Printf("Session %s terminated.\n", sessionID)
@@ -230,9 +231,38 @@ var sessionStopCmd = &cobra.Command{
 	}),
 }
 
+// completeSessions suggests the IDs of the sessions playing now with what they play
+var completeSessions = commands.Complete(true, func(ctx context.Context) ([]commands.Suggestion, error) {
+	client, err := plex.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.SDK.Status.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var suggestions []commands.Suggestion
+	if res.Object != nil && res.Object.MediaContainer != nil {
+		for _, m := range res.Object.MediaContainer.Metadata {
+			if m.Session == nil {
+				continue
+			}
+			desc := m.Title
+			if m.User != nil {
+				desc = fmt.Sprintf("%s (%s)", m.Title, ui.PtrToString(m.User.Title))
+			}
+			suggestions = append(suggestions, commands.Suggestion{Value: ui.PtrToString(m.Session.ID), Desc: desc})
+		}
+	}
+	return suggestions, nil
+})
+
 func init() {
 	rootCmd.AddCommand(sessionCmd)
 	sessionCmd.AddCommand(sessionListCmd)
 	sessionCmd.AddCommand(sessionShowCmd)
 	sessionCmd.AddCommand(sessionStopCmd)
+
+	sessionShowCmd.ValidArgsFunction = completeSessions
+	sessionStopCmd.ValidArgsFunction = completeSessions
 }
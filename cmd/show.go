@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/presenters"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+var showMissingSpecials bool
+
+var showCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Inspect TV shows",
+	GroupID: "media",
+}
+
+var showMissingCmd = &cobra.Command{
+	Use:   "missing [show_id...]",
+	Short: "List episodes missing from a show",
+	Long: `List the episodes missing from a show, based on gaps in each season's episode numbers
+and seasons missing between the first and last one in the library, or left without episodes
+according to their leaf count. Episodes after the last one present in a season cannot be
+detected. Use -o json for a machine readable list: an object for one show, a list of objects
+when several are given.`,
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		var results []showGaps
+		err := commands.ForEachKey(ctx, client, cmd, args, opts, func(showKey string, opts *commands.PlexCtlOptions) error {
+			meta, err := plex.GetMetadata(ctx, showKey, false)
+			if err != nil {
+				return fmt.Errorf("failed to get show: %w", err)
+			}
+			if meta.Type != "show" {
+				return fmt.Errorf("item %s is a %s, not a show", showKey, meta.Type)
+			}
+
+			gaps, err := plex.FindShowGaps(ctx, showKey, showMissingSpecials)
+			if err != nil {
+				return err
+			}
+			res := showGaps{Show: meta.Title, RatingKey: showKey, Seasons: []plex.SeasonGaps{}, Episodes: []string{}}
+			for _, g := range gaps {
+				res.Seasons = append(res.Seasons, g)
+				res.Episodes = append(res.Episodes, g.EpisodeCodes()...)
+			}
+			results = append(results, res)
+			return nil
+		})
+		// Shows that were checked are printed even when others failed
+		if len(results) == 0 {
+			return err
+		}
+		if printErr := printShowGaps(results, len(results) > 1 || err != nil, opts); printErr != nil {
+			return printErr
+		}
+		return err
+	}),
+}
+
+// showGaps is the result of show missing for one show
+type showGaps struct {
+	Show      string            `json:"show"`
+	RatingKey string            `json:"rating_key"`
+	Seasons   []plex.SeasonGaps `json:"seasons"`
+	Episodes  []string          `json:"episodes"`
+}
+
+// printShowGaps prints the gaps of every show in one table, or one json document: an object for a
+// single show, a list when several were asked for. Shows without gaps get a message in titled formats.
+func printShowGaps(results []showGaps, several bool, opts *commands.PlexCtlOptions) error {
+	titled := ui.TitledFormat(opts.OutputFormat)
+
+	var rows [][]string
+	var withGaps []showGaps
+	for _, res := range results {
+		if titled && len(res.Seasons) == 0 {
+			ui.RenderSuccess(fmt.Sprintf("No missing episodes in %s.", res.Show))
+			continue
+		}
+		withGaps = append(withGaps, res)
+		for _, g := range res.Seasons {
+			missing := strings.Join(g.EpisodeCodes(), ", ")
+			if g.Absent {
+				missing = "entire season"
+			}
+			row := []string{fmt.Sprintf("%d", g.Season), g.RatingKey, fmt.Sprintf("%d", g.Present), missing}
+			if several {
+				row = append([]string{res.Show}, row...)
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(withGaps) == 0 {
+		return nil
+	}
+
+	p := presenters.SimplePresenter{
+		T: fmt.Sprintf("Missing episodes in %s", withGaps[0].Show),
+		H: []string{"SEASON", "ID", "PRESENT", "MISSING"},
+		R: rows,
+	}
+	if several {
+		p.T = "Missing episodes"
+		p.H = append([]string{"SHOW"}, p.H...)
+		p.RawData = withGaps
+	} else {
+		p.RawData = withGaps[0]
+	}
+	return commands.Print(p, opts)
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.AddCommand(showMissingCmd)
+
+	showMissingCmd.Flags().BoolVar(&showMissingSpecials, "specials", false, "Also check the specials season for gaps")
+	commands.AcceptKeys(showMissingCmd, "ratingKey", resolve.Show)
+}
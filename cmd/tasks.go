@@ -13,6 +13,8 @@ import (
 	"github.com/ygelfand/plexctl/internal/ui"
 )
 
+var tasksWait bool
+
 var tasksCmd = &cobra.Command{
 	Use:     "tasks",
 	Short:   "Manage background butler tasks",
@@ -98,17 +100,19 @@ var tasksStartCmd = &cobra.Command{
 			}
 		}
 
-		slog.Debug("SDK: Starting task", "task_name", taskName)
-		_, err := client.SDK.Butler.StartTask(ctx, operations.StartTaskRequest{
-			ButlerTask: operations.PathParamButlerTask(taskName),
+		return startAndFollow(ctx, client, tasksWait, func() error {
+			slog.Debug("SDK: Starting task", "task_name", taskName)
+			_, err := client.SDK.Butler.StartTask(ctx, operations.StartTaskRequest{
+				ButlerTask: operations.PathParamButlerTask(taskName),
+			})
+			if err != nil {
+				slog.Error("SDK: Start task failed", "task_name", taskName, "error", err)
+				return err
+			}
+			slog.Debug("SDK: Task started successfully", "task_name", taskName)
+			fmt.Printf("Task %s started.\n", taskName)
+			return nil
 		})
-		if err != nil {
-			slog.Error("SDK: Start task failed", "task_name", taskName, "error", err)
-			return err
-		}
-		slog.Debug("SDK: Task started successfully", "task_name", taskName)
-		fmt.Printf("Task %s started.\n", taskName)
-		return nil
 	}),
 }
 
@@ -155,9 +159,31 @@ var tasksStopCmd = &cobra.Command{
 	}),
 }
 
+// completeTasks suggests butler task names with their titles
+var completeTasks = commands.Complete(true, func(ctx context.Context) ([]commands.Suggestion, error) {
+	return commands.CachedSuggestions(ctx, "tasks", func(ctx context.Context, client *plex.Client) ([]commands.Suggestion, error) {
+		res, err := client.SDK.Butler.GetTasks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var suggestions []commands.Suggestion
+		if res.Object != nil && res.Object.ButlerTasks != nil {
+			for _, t := range res.Object.ButlerTasks.ButlerTask {
+				suggestions = append(suggestions, commands.Suggestion{Value: ui.PtrToString(t.Name), Desc: ui.PtrToString(t.Title)})
+			}
+		}
+		return suggestions, nil
+	})
+})
+
 func init() {
 	rootCmd.AddCommand(tasksCmd)
 	tasksCmd.AddCommand(tasksListCmd)
 	tasksCmd.AddCommand(tasksStartCmd)
 	tasksCmd.AddCommand(tasksStopCmd)
+
+	tasksStartCmd.ValidArgsFunction = completeTasks
+	tasksStopCmd.ValidArgsFunction = completeTasks
+
+	tasksStartCmd.Flags().BoolVar(&tasksWait, "wait", false, "Wait for the activities started by the task to finish, showing their progress")
 }
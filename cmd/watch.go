@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/commands"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/ui"
+	"github.com/ygelfand/plexctl/internal/watch"
+)
+
+var (
+	watchMappings []string
+	watchDebounce time.Duration
+	watchDryRun   bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch local media folders and scan changed folders on the server",
+	Long: `Watch local media folders and trigger a targeted scan of each changed folder once
+changes settle. Each --map translates a local folder to the path the server sees it
+under, the library section is picked from the server path.
+
+  plexctl watch --map /mnt/media/tv=/data/tv --map /mnt/media/movies=/data/movies`,
+	GroupID: "media",
+	RunE: commands.RunWithServer(func(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *commands.PlexCtlOptions) error {
+		if len(watchMappings) == 0 {
+			return fmt.Errorf("at least one --map /local/path=/server/path is required")
+		}
+		var mappings []watch.Mapping
+		for _, m := range watchMappings {
+			mapping, err := watch.ParseMapping(m)
+			if err != nil {
+				return err
+			}
+			mappings = append(mappings, mapping)
+		}
+
+		sections, err := watchSections(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		w := watch.New(mappings, sections, watch.Options{
+			Debounce: watchDebounce,
+			DryRun:   watchDryRun,
+		}, func(ctx context.Context, section watch.Section, serverPath string) error {
+			_, err := client.SDK.Library.RefreshSection(ctx, operations.RefreshSectionRequest{
+				SectionID: section.ID,
+				Path:      &serverPath,
+			})
+			return err
+		})
+		return w.Run(ctx)
+	}),
+}
+
+// watchSections returns the library sections with the server folders they cover
+func watchSections(ctx context.Context, client *plex.Client) ([]watch.Section, error) {
+	slog.Debug("SDK: Fetching sections")
+	res, err := client.SDK.Library.GetSections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	if res.Object == nil || res.Object.MediaContainer == nil {
+		return nil, nil
+	}
+
+	var sections []watch.Section
+	for _, d := range res.Object.MediaContainer.Directory {
+		id, err := strconv.ParseInt(ui.PtrToString(d.Key), 10, 64)
+		if err != nil {
+			continue
+		}
+		section := watch.Section{ID: id, Title: ui.PtrToString(d.Title)}
+		for _, l := range d.Location {
+			if p, ok := l.Path.(string); ok && p != "" {
+				section.Locations = append(section.Locations, p)
+			}
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringArrayVar(&watchMappings, "map", nil, "Map a local folder to the server's path for it (/local/path=/server/path), repeatable")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 10*time.Second, "Wait this long after the last change before scanning")
+	watchCmd.Flags().BoolVar(&watchDryRun, "dry-run", false, "Log the scans that would be triggered without triggering them")
+}
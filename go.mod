@@ -9,8 +9,10 @@ require (
 	github.com/charmbracelet/bubbletea v1.3.10
 	github.com/charmbracelet/lipgloss v1.1.0
 	github.com/dexterlb/mpvipc v0.0.0-20241005113212-7cdefca0e933
+	github.com/fsnotify/fsnotify v1.9.0
 	github.com/kyokomi/emoji/v2 v2.2.13
 	github.com/lovelydeng/gomoji v0.0.0-20221120141925-ea446dc92ac0
+	github.com/ohler55/ojg v1.28.5
 	github.com/olekukonko/tablewriter v1.1.3
 	github.com/peterbourgon/diskv v2.0.1+incompatible
 	github.com/pkg/browser v0.0.0-20240102092130-5ac0b6a4141c
@@ -55,7 +57,6 @@ require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.6 // indirect
 	github.com/doordash-oss/oapi-codegen-dd/v3 v3.66.2 // indirect
 	github.com/erikgeiser/coninput v0.0.0-20211004153227-1c3628e74d0f // indirect
-	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.11 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
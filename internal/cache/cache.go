@@ -8,20 +8,33 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/peterbourgon/diskv"
 	"github.com/ygelfand/plexctl/internal/config"
 )
 
+// CacheEntry is the on-disk envelope. The metadata fields come before Value so they can be
+// read without loading the whole payload.
 type CacheEntry struct {
-	Value     json.RawMessage `json:"value"`
 	ExpiresAt int64           `json:"expires_at"` // Unix timestamp, 0 for infinite
+	Context   string          `json:"context,omitempty"`
+	Key       string          `json:"key,omitempty"`
+	Desc      string          `json:"desc,omitempty"` // what a hashed key was generated from
+	Value     json.RawMessage `json:"value"`
 }
 
 type Manager struct {
-	dv *diskv.Diskv
+	dv       *diskv.Diskv
+	basePath string
+	scope    string // active config context, keeps entries of different accounts apart
+
+	// mu orders writes against the erasures of a prune running while the command uses the cache
+	mu sync.Mutex
 }
 
 var globalManager *Manager
@@ -42,11 +55,13 @@ func Get(path string) (*Manager, error) {
 
 	dv := diskv.New(diskv.Options{
 		BasePath:     path,
+		TempDir:      filepath.Join(path, ".tmp"), // files are written whole, for other processes pruning
 		Transform:    flatTransform,
 		CacheSizeMax: 1024 * 1024, // 1MB
 	})
 
-	globalManager = &Manager{dv: dv}
+	globalManager = &Manager{dv: dv, basePath: path, scope: config.Get().ActiveContext}
+	globalManager.migrate(config.Get())
 	return globalManager, nil
 }
 
@@ -76,23 +91,61 @@ func (m *Manager) GenerateKey(namespace string, params any) string {
 	return m.HashKey(fmt.Sprintf("%s:%s:%s", namespace, op, string(p)))
 }
 
+// describe names the operation of a generated key with the parameters that are set, such as
+// "s1 GetMetadataItemRequest Ids=[200] IncludeExtras=1", for listing the cache
+func describe(namespace string, params any) string {
+	parts := []string{namespace}
+	v := reflect.Indirect(reflect.ValueOf(params))
+	if !v.IsValid() {
+		return namespace
+	}
+	parts = append(parts, v.Type().Name())
+	if v.Kind() != reflect.Struct {
+		return strings.Join(append(parts, fmt.Sprint(v.Interface())), " ")
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		f := v.Field(i)
+		for (f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface) && !f.IsNil() {
+			f = f.Elem()
+		}
+		if !field.IsExported() || f.IsZero() {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", field.Name, f.Interface()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// diskKey maps a logical key to its on-disk name, scoped to the active context and
+// prefixed with its namespace so maintenance can group files without reading them
+func (m *Manager) diskKey(key string) string {
+	return m.diskName(m.scope, key)
+}
+
+func (m *Manager) diskName(scope, key string) string {
+	if scope != "" {
+		return namespaceOf(key) + "-" + m.HashKey(scope+"/"+key)
+	}
+	return namespaceOf(key) + "-" + m.HashKey(key)
+}
+
 // Set stores data in the cache under the given key with a TTL.
 func (m *Manager) Set(key string, val any, ttl time.Duration) error {
+	return m.set(key, "", val, ttl)
+}
+
+// set stores data like Set, along with desc describing a hashed key
+func (m *Manager) set(key, desc string, val any, ttl time.Duration) error {
 	if config.Get().NoCache {
 		return nil
 	}
-	safeKey := m.HashKey(key)
+	safeKey := m.diskKey(key)
 	slog.Log(context.Background(), config.LevelTrace, "Cache: SET", "key", key, "safeKey", safeKey, "ttl", ttl)
-	var data []byte
-	var err error
-
-	if b, ok := val.([]byte); ok {
-		data = b
-	} else {
-		data, err = json.Marshal(val)
-		if err != nil {
-			return err
-		}
+	// []byte values such as images are stored base64 encoded by json.Marshal
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
 	}
 
 	var expiresAt int64
@@ -101,8 +154,11 @@ func (m *Manager) Set(key string, val any, ttl time.Duration) error {
 	}
 
 	entry := CacheEntry{
-		Value:     data,
 		ExpiresAt: expiresAt,
+		Context:   m.scope,
+		Key:       key,
+		Desc:      desc,
+		Value:     data,
 	}
 
 	entryData, err := json.Marshal(entry)
@@ -110,6 +166,8 @@ func (m *Manager) Set(key string, val any, ttl time.Duration) error {
 		return err
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.dv.Write(safeKey, entryData)
 }
 
@@ -118,7 +176,7 @@ func (m *Manager) Get(key string, val interface{}) error {
 	if config.Get().NoCache {
 		return fmt.Errorf("caching is disabled")
 	}
-	safeKey := m.HashKey(key)
+	safeKey := m.diskKey(key)
 	slog.Log(context.Background(), config.LevelTrace, "Cache: GET", "key", key, "safeKey", safeKey)
 	entryData, err := m.dv.Read(safeKey)
 	if err != nil {
@@ -136,16 +194,16 @@ func (m *Manager) Get(key string, val interface{}) error {
 		return fmt.Errorf("cache entry expired")
 	}
 
-	if b, ok := val.(*[]byte); ok {
-		*b = entry.Value
-		return nil
-	}
-
+	m.touch(safeKey)
 	return json.Unmarshal(entry.Value, val)
 }
 
 // WithCache is a helper that tries to get data from cache first, otherwise calls the fetcher
 func WithCache[T any](m *Manager, key string, ttl time.Duration, val *T, fetcher func() (*T, error)) error {
+	return withCache(m, key, "", ttl, val, fetcher)
+}
+
+func withCache[T any](m *Manager, key, desc string, ttl time.Duration, val *T, fetcher func() (*T, error)) error {
 	if ttl > 0 {
 		if err := m.Get(key, val); err == nil {
 			return nil
@@ -159,17 +217,18 @@ func WithCache[T any](m *Manager, key string, ttl time.Duration, val *T, fetcher
 	if fetched != nil {
 		*val = *fetched
 		if ttl > 0 {
-			return m.Set(key, fetched, ttl)
+			return m.set(key, desc, fetched, ttl)
 		}
 	}
 
 	return nil
 }
 
-// AutoCache automatically generates a key based on the request parameters type.
+// AutoCache automatically generates a key based on the request parameters type. The entry keeps the
+// request it was generated from, so that it can be listed by more than its hash.
 func AutoCache[T any](m *Manager, namespace string, req any, ttl time.Duration, val *T, fetcher func() (*T, error)) error {
 	key := m.GenerateKey(namespace, req)
-	return WithCache(m, key, ttl, val, fetcher)
+	return withCache(m, key, describe(namespace, req), ttl, val, fetcher)
 }
 
 // Delete removes a key from the cache
@@ -177,5 +236,5 @@ func (m *Manager) Delete(key string) error {
 	if config.Get().NoCache {
 		return nil
 	}
-	return m.dv.Erase(m.HashKey(key))
+	return m.dv.Erase(m.diskKey(key))
 }
@@ -0,0 +1,371 @@
+package cache
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ygelfand/plexctl/internal/config"
+)
+
+// Namespaces group cache entries by what they hold
+const (
+	NamespacePosters  = "posters"
+	NamespaceImages   = "images"
+	NamespaceMetadata = "metadata"
+	NamespaceIndex    = "index"
+	NamespaceComplete = "completion"
+	NamespaceLegacy   = "legacy" // files not named by namespace, left over from older versions
+)
+
+// Namespaces lists every namespace new entries can be written to
+var Namespaces = []string{NamespacePosters, NamespaceImages, NamespaceMetadata, NamespaceIndex, NamespaceComplete}
+
+const (
+	pruneMarker   = ".last_prune"
+	pruneInterval = time.Hour
+
+	// layoutMarker records that files of older versions, named by a hash of the key alone, were migrated
+	layoutMarker = ".layout_v2"
+)
+
+// Entry describes a single file in the cache directory
+type Entry struct {
+	File      string    `json:"file"`
+	Key       string    `json:"key"`
+	Desc      string    `json:"desc,omitempty"` // the request a hashed key was generated from
+	Namespace string    `json:"namespace"`
+	Context   string    `json:"context"`
+	Size      int64     `json:"size"`
+	LastUsed  time.Time `json:"last_used"`
+	ExpiresAt time.Time `json:"expires_at"` // zero when the entry never expires
+}
+
+// Expired reports whether the entry is past its TTL at now
+func (e Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// NamespaceStats summarizes the entries of one namespace
+type NamespaceStats struct {
+	Namespace string `json:"namespace"`
+	Entries   int    `json:"entries"`
+	Expired   int    `json:"expired"`
+	Size      int64  `json:"size"`
+}
+
+// PruneResult reports what a prune removed
+type PruneResult struct {
+	Expired int   `json:"expired"`
+	Evicted int   `json:"evicted"`
+	Freed   int64 `json:"freed"`
+}
+
+func namespaceOf(key string) string {
+	switch {
+	case strings.HasPrefix(key, "rendered_poster/"):
+		return NamespacePosters
+	case strings.Contains(key, "/img/"):
+		return NamespaceImages
+	case strings.HasSuffix(key, "/search_index"):
+		return NamespaceIndex
+	case strings.Contains(key, "/completion/"):
+		return NamespaceComplete
+	default:
+		return NamespaceMetadata
+	}
+}
+
+// Dir returns the directory the cache is stored in
+func (m *Manager) Dir() string {
+	return m.basePath
+}
+
+// Entries lists every file in the cache directory, reading only the entry headers
+func (m *Manager) Entries() ([]Entry, error) {
+	files, err := os.ReadDir(m.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Entry
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+
+		e := Entry{
+			File:      f.Name(),
+			Namespace: NamespaceLegacy,
+			Size:      info.Size(),
+			LastUsed:  info.ModTime(),
+		}
+		if ns, _, ok := strings.Cut(f.Name(), "-"); ok {
+			e.Namespace = ns
+		}
+		if h, err := readHeader(filepath.Join(m.basePath, f.Name())); err == nil {
+			e.Key = h.Key
+			e.Desc = h.Desc
+			e.Context = h.Context
+			if h.ExpiresAt > 0 {
+				e.ExpiresAt = time.Unix(h.ExpiresAt, 0)
+			}
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+// Stats aggregates the cache entries by namespace
+func (m *Manager) Stats() ([]NamespaceStats, error) {
+	entries, err := m.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	byNs := make(map[string]*NamespaceStats)
+	for _, ns := range Namespaces {
+		byNs[ns] = &NamespaceStats{Namespace: ns}
+	}
+	for _, e := range entries {
+		s, ok := byNs[e.Namespace]
+		if !ok {
+			s = &NamespaceStats{Namespace: e.Namespace}
+			byNs[e.Namespace] = s
+		}
+		s.Entries++
+		s.Size += e.Size
+		if e.Expired(now) {
+			s.Expired++
+		}
+	}
+
+	res := make([]NamespaceStats, 0, len(byNs))
+	for _, ns := range Namespaces {
+		res = append(res, *byNs[ns])
+		delete(byNs, ns)
+	}
+	extra := make([]string, 0, len(byNs))
+	for ns := range byNs {
+		extra = append(extra, ns)
+	}
+	sort.Strings(extra)
+	for _, ns := range extra {
+		res = append(res, *byNs[ns])
+	}
+	return res, nil
+}
+
+// Clear removes all entries in the given namespaces, or everything when none are given
+func (m *Manager) Clear(namespaces ...string) (int, int64, error) {
+	entries, err := m.Entries()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var count int
+	var freed int64
+	for _, e := range entries {
+		if len(namespaces) > 0 && !slices.Contains(namespaces, e.Namespace) {
+			continue
+		}
+		if err := m.dv.Erase(e.File); err != nil {
+			continue
+		}
+		count++
+		freed += e.Size
+	}
+	slog.Debug("Cache: Cleared entries", "count", count, "freed", freed, "namespaces", namespaces)
+	return count, freed, nil
+}
+
+// Prune removes expired entries, then evicts the least recently used entries until the cache
+// fits in maxSize bytes. Eviction goes down to 90% of maxSize so the next writes do not
+// immediately trigger another round. A maxSize of 0 disables eviction.
+func (m *Manager) Prune(maxSize int64) (PruneResult, error) {
+	var res PruneResult
+	entries, err := m.Entries()
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	var total int64
+	live := entries[:0]
+	for _, e := range entries {
+		if e.Expired(now) {
+			if m.erase(e) {
+				res.Expired++
+				res.Freed += e.Size
+			}
+			continue
+		}
+		total += e.Size
+		live = append(live, e)
+	}
+
+	if maxSize > 0 && total > maxSize {
+		target := maxSize / 10 * 9
+		sort.Slice(live, func(i, j int) bool { return live[i].LastUsed.Before(live[j].LastUsed) })
+		for _, e := range live {
+			if total <= target {
+				break
+			}
+			if m.erase(e) {
+				res.Evicted++
+				res.Freed += e.Size
+				total -= e.Size
+			}
+		}
+	}
+
+	_ = os.WriteFile(filepath.Join(m.basePath, pruneMarker), nil, 0o644)
+	slog.Debug("Cache: Pruned", "expired", res.Expired, "evicted", res.Evicted, "freed", res.Freed, "remaining", total)
+	return res, nil
+}
+
+// erase removes an entry listed by Entries unless it was written, used or removed since, as a prune
+// runs while the command is using the cache
+func (m *Manager) erase(e Entry) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	info, err := os.Stat(filepath.Join(m.basePath, e.File))
+	if err != nil || !info.ModTime().Equal(e.LastUsed) {
+		return false
+	}
+	return m.dv.Erase(e.File) == nil
+}
+
+// PruneInBackground starts a prune in a goroutine unless one completed within the last hour, returning
+// a channel closed once it is done. Only a completed prune counts, so one cut short by the process
+// exiting is tried again by the next run.
+func (m *Manager) PruneInBackground(maxSize int64) <-chan struct{} {
+	done := make(chan struct{})
+	marker := filepath.Join(m.basePath, pruneMarker)
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < pruneInterval {
+		close(done)
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		if _, err := m.Prune(maxSize); err != nil {
+			slog.Debug("Cache: Background prune failed", "error", err)
+		}
+	}()
+	return done
+}
+
+// migrate renames the files of older versions, named by a hash of the key alone, once per cache
+// directory. Search indexes are kept, as they never expire and take long to build. Everything else is
+// deleted and fetched again when needed.
+func (m *Manager) migrate(cfg *config.Config) {
+	marker := filepath.Join(m.basePath, layoutMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+
+	scopes := []string{""}
+	for name := range cfg.Contexts {
+		scopes = append(scopes, name)
+	}
+	for id := range cfg.Servers {
+		key := id + "/search_index"
+		for _, scope := range scopes {
+			old := key
+			if scope != "" {
+				old = scope + "/" + key
+			}
+			// Older entries hold the same value without the key and context they were written for
+			data, err := m.dv.Read(m.HashKey(old))
+			if err != nil {
+				continue
+			}
+			var entry CacheEntry
+			if json.Unmarshal(data, &entry) != nil {
+				continue
+			}
+			entry.Context, entry.Key = scope, key
+			if data, err = json.Marshal(entry); err == nil {
+				_ = m.dv.Write(m.diskName(scope, key), data)
+			}
+		}
+	}
+
+	files, err := os.ReadDir(m.basePath)
+	if err != nil {
+		return
+	}
+	removed := 0
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || strings.Contains(f.Name(), "-") {
+			continue
+		}
+		if os.Remove(filepath.Join(m.basePath, f.Name())) == nil {
+			removed++
+		}
+	}
+	_ = os.WriteFile(marker, nil, 0o644)
+	slog.Debug("Cache: Migrated files of older versions", "removed", removed)
+}
+
+// touch marks an entry as recently used for LRU eviction
+func (m *Manager) touch(safeKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(m.basePath, safeKey), now, now)
+}
+
+// readHeader decodes the entry fields that precede the value without reading the payload
+func readHeader(path string) (CacheEntry, error) {
+	var h CacheEntry
+	f, err := os.Open(path)
+	if err != nil {
+		return h, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	if _, err := dec.Token(); err != nil {
+		return h, err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return h, err
+		}
+		if tok == "value" && h.Key != "" {
+			// The header is complete, the value follows it
+			break
+		}
+		var target any
+		switch tok {
+		case "expires_at":
+			target = &h.ExpiresAt
+		case "context":
+			target = &h.Context
+		case "key":
+			target = &h.Key
+		case "desc":
+			target = &h.Desc
+		default:
+			// Legacy entries store the value first, so skip over it
+			target = &json.RawMessage{}
+		}
+		if err := dec.Decode(target); err != nil {
+			return h, err
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/peterbourgon/diskv"
+)
+
+type testRequest struct {
+	SectionID string
+	Start     *int
+	Size      *int
+}
+
+func newTestManager(t *testing.T) *Manager {
+	dir := t.TempDir()
+	return &Manager{
+		dv:       diskv.New(diskv.Options{BasePath: dir, TempDir: filepath.Join(dir, ".tmp")}),
+		basePath: dir,
+	}
+}
+
+func TestEntriesDescribeGeneratedKeys(t *testing.T) {
+	m := newTestManager(t)
+	size := 100
+	var val string
+	err := AutoCache(m, "s1", testRequest{SectionID: "2", Size: &size}, time.Hour, &val, func() (*string, error) {
+		v := "items"
+		return &v, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := m.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if want := "s1 testRequest SectionID=2 Size=100"; entries[0].Desc != want {
+		t.Errorf("desc = %q, want %q", entries[0].Desc, want)
+	}
+}
+
+func TestPruneSkipsEntriesChangedSinceListed(t *testing.T) {
+	m := newTestManager(t)
+	for _, key := range []string{"s1/a", "s1/b", "s1/c"} {
+		if err := m.Set(key, "value", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := m.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The command removes one entry and uses another while the prune runs
+	if err := m.Delete("s1/a"); err != nil {
+		t.Fatal(err)
+	}
+	used := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(m.basePath, m.diskKey("s1/b")), used, used); err != nil {
+		t.Fatal(err)
+	}
+
+	erased := 0
+	for _, e := range entries {
+		if m.erase(e) {
+			erased++
+		}
+	}
+	if erased != 1 {
+		t.Errorf("erased %d entries, want only the unchanged one", erased)
+	}
+	var got string
+	if err := m.Get("s1/b", &got); err != nil {
+		t.Errorf("entry used during the prune was evicted: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+// Package clierr classifies the errors commands fail with, so scripts can tell a server that is down
+// from an ID that does not exist by the exit code or the structured error printed with -o json.
+package clierr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/sdkerrors"
+)
+
+// Kind is the class of an error
+type Kind int
+
+const (
+	General Kind = iota
+	Usage
+	Auth
+	NotFound
+	Unreachable
+	Partial
+	Cancelled
+)
+
+// kindInfo is the code and exit code of each kind
+var kindInfo = map[Kind]struct {
+	code string
+	exit int
+}{
+	General:     {"error", 1},
+	Usage:       {"usage", 2},
+	Auth:        {"auth", 3},
+	NotFound:    {"not_found", 4},
+	Unreachable: {"unreachable", 5},
+	Partial:     {"partial", 6},
+	Cancelled:   {"cancelled", 130},
+}
+
+// Code is the name of the kind in structured errors, e.g. not_found
+func (k Kind) Code() string {
+	return kindInfo[k].code
+}
+
+// ExitCode is the exit status of a command failing with an error of this kind
+func (k Kind) ExitCode() int {
+	return kindInfo[k].exit
+}
+
+// Error is an error of a known kind, with the HTTP status of the response it came from if any
+type Error struct {
+	Kind   Kind
+	Status int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of the given kind with a formatted message, wrapping errors given with %w
+func New(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap marks err as being of the given kind, nil stays nil
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// FromStatus returns an error for a failed HTTP response, its kind following the status
+func FromStatus(status int, format string, args ...any) error {
+	return &Error{Kind: statusKind(status), Status: status, Err: fmt.Errorf(format, args...)}
+}
+
+func statusKind(status int) Kind {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return Auth
+	case status == http.StatusNotFound:
+		return NotFound
+	case status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout:
+		return Unreachable
+	default:
+		return General
+	}
+}
+
+// KindOf classifies an error: errors marked with a kind keep it, failed responses from the SDK are
+// classified by their status and failed connections are Unreachable
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	if status := StatusOf(err); status != 0 {
+		return statusKind(status)
+	}
+	var urlErr *url.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Cancelled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &urlErr), errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return Unreachable
+	}
+	return General
+}
+
+// ExitCode is the exit status of a command failing with err
+func ExitCode(err error) int {
+	return KindOf(err).ExitCode()
+}
+
+// StatusOf returns the HTTP status of the response an error came from, 0 when there was none
+func StatusOf(err error) int {
+	var e *Error
+	if errors.As(err, &e) && e.Status != 0 {
+		return e.Status
+	}
+	var sdkErr *sdkerrors.SDKError
+	if errors.As(err, &sdkErr) {
+		return sdkErr.StatusCode
+	}
+	for inner := err; inner != nil; inner = errors.Unwrap(inner) {
+		if body, ok := errorBody(inner.Error()); ok && len(body.Errors) > 0 {
+			return body.Errors[0].Status
+		}
+	}
+	return 0
+}
+
+// apiErrors is the body of the typed SDK errors, which print themselves as json
+type apiErrors struct {
+	Errors []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  int    `json:"status"`
+	} `json:"errors"`
+}
+
+func errorBody(s string) (apiErrors, bool) {
+	var body apiErrors
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || json.Unmarshal([]byte(s), &body) != nil {
+		return body, false
+	}
+	return body, true
+}
+
+// Message returns the message of err with the errors of the SDK, which carry the raw response body,
+// replaced by the status and the message the server gave, if any
+func Message(err error) string {
+	msg := err.Error()
+	var sdkErr *sdkerrors.SDKError
+	if errors.As(err, &sdkErr) {
+		msg = strings.Replace(msg, sdkErr.Error(), describeResponse(sdkErr.StatusCode, sdkErr.Body), 1)
+	}
+	for inner := err; inner != nil; inner = errors.Unwrap(inner) {
+		if body, ok := errorBody(inner.Error()); ok && len(body.Errors) > 0 {
+			msg = strings.Replace(msg, inner.Error(), describeErrors(body), 1)
+			break
+		}
+	}
+	return msg
+}
+
+// describeResponse summarises a failed response, keeping short plain text or json messages from the body
+func describeResponse(status int, body string) string {
+	msg := fmt.Sprintf("server returned %d %s", status, http.StatusText(status))
+	if parsed, ok := errorBody(body); ok && len(parsed.Errors) > 0 {
+		return msg + ": " + describeErrors(parsed)
+	}
+	body = strings.TrimSpace(body)
+	if body != "" && !strings.HasPrefix(body, "<") && !strings.Contains(body, "\n") && len(body) <= 200 {
+		return msg + ": " + body
+	}
+	return msg
+}
+
+func describeErrors(body apiErrors) string {
+	var messages []string
+	for _, e := range body.Errors {
+		switch {
+		case e.Message != "":
+			messages = append(messages, e.Message)
+		case e.Status != 0:
+			messages = append(messages, fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)))
+		}
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Report is the structured form of an error, printed on stderr for formats read by scripts
+type Report struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Status   int    `json:"status,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// NewReport describes err for scripts
+func NewReport(err error) Report {
+	kind := KindOf(err)
+	return Report{Code: kind.Code(), Message: Message(err), Status: StatusOf(err), ExitCode: kind.ExitCode()}
+}
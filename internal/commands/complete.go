@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ygelfand/plexctl/internal/cache"
+	"github.com/ygelfand/plexctl/internal/config"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/resolve"
+)
+
+const (
+	// CompletionCacheTTL keeps lists fetched for shell completion briefly, so repeated tabs stay fast
+	CompletionCacheTTL = 5 * time.Minute
+
+	// completionLimit caps the suggestions offered at once
+	completionLimit = 100
+)
+
+// Suggestion is a value offered by shell completion
+type Suggestion struct {
+	Value string `json:"value"`
+	Desc  string `json:"desc"`
+
+	// Name is offered instead of Value once the user starts typing it, e.g. a title for an ID,
+	// with Value as its description
+	Name string `json:"name,omitempty"`
+}
+
+// Complete returns a completion function offering the suggestions from list, with their descriptions.
+// With single only the first argument is completed, otherwise values already given are left out.
+func Complete(single bool, list func(ctx context.Context) ([]Suggestion, error)) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if single && len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		suggestions, err := list(ctx)
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("completion failed: %v", err), true)
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return matchSuggestions(suggestions, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// matchSuggestions keeps the suggestions whose value, or name ignoring case, starts with what was typed
+func matchSuggestions(suggestions []Suggestion, given []string, toComplete string) []cobra.Completion {
+	var completions []cobra.Completion
+	for _, s := range suggestions {
+		if len(completions) >= completionLimit {
+			break
+		}
+		if slices.Contains(given, s.Value) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(s.Value, toComplete):
+			completions = append(completions, cobra.CompletionWithDesc(s.Value, s.Desc))
+		case toComplete != "" && s.Name != "" && strings.HasPrefix(strings.ToLower(s.Name), strings.ToLower(toComplete)):
+			completions = append(completions, cobra.CompletionWithDesc(s.Name, s.Value))
+		}
+	}
+	return completions
+}
+
+// CompleteKind completes the IDs of a kind with their titles as descriptions, or the titles themselves
+// once one is being typed. Items and shows come from the search index, the rest is cached briefly.
+func CompleteKind(kind resolve.Kind, single bool) cobra.CompletionFunc {
+	return Complete(single, func(ctx context.Context) ([]Suggestion, error) {
+		list := func(ctx context.Context, client *plex.Client) ([]Suggestion, error) {
+			candidates, err := resolve.List(ctx, client, kind)
+			if err != nil {
+				return nil, err
+			}
+			suggestions := make([]Suggestion, len(candidates))
+			for i, c := range candidates {
+				desc := c.Label()
+				if kind == resolve.Item {
+					desc = fmt.Sprintf("%s, %s", desc, c.Type)
+				}
+				suggestions[i] = Suggestion{Value: c.ID, Desc: desc, Name: c.Label()}
+			}
+			return suggestions, nil
+		}
+		if kind == resolve.Item || kind == resolve.Show {
+			client, err := plex.NewClient()
+			if err != nil {
+				return nil, err
+			}
+			return list(ctx, client)
+		}
+		return CachedSuggestions(ctx, string(kind), list)
+	})
+}
+
+// CachedSuggestions returns the suggestions from list, kept in the cache for CompletionCacheTTL
+// under name for the active server
+func CachedSuggestions(ctx context.Context, name string, list func(ctx context.Context, client *plex.Client) ([]Suggestion, error)) ([]Suggestion, error) {
+	cfg := config.Get()
+	serverID, _, ok := cfg.GetActiveServer()
+	if !ok {
+		return nil, fmt.Errorf("no active server")
+	}
+	cm, err := cache.Get(cfg.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	err = cache.WithCache(cm, fmt.Sprintf("%s/completion/%s", serverID, name), CompletionCacheTTL, &suggestions, func() (*[]Suggestion, error) {
+		client, err := plex.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		fetched, err := list(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		return &fetched, nil
+	})
+	return suggestions, err
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/resolve"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+const (
+	// keyFieldAnnotation holds the NDJSON field a command reads its IDs from
+	keyFieldAnnotation = "plexctl/key-field"
+
+	// keyKindAnnotation holds what the IDs of a command name, so references can be resolved
+	keyKindAnnotation = "plexctl/key-kind"
+)
+
+// AcceptKeys lets a command take one or more IDs as arguments, read them from stdin when the only
+// argument is - or --stdin is set, or pick them interactively when none are given. field is the NDJSON
+// field holding the ID, such as ratingKey, and kind is what the IDs name, so that titles and names can
+// be given instead.
+func AcceptKeys(cmd *cobra.Command, field string, kind resolve.Kind) {
+	cmd.Flags().Bool("stdin", false, fmt.Sprintf("Read IDs from stdin, one per line or as NDJSON objects with a %s field", field))
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[keyFieldAnnotation] = field
+	cmd.Annotations[keyKindAnnotation] = string(kind)
+	cmd.ValidArgsFunction = CompleteKind(kind, false)
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		stdin, _ := cmd.Flags().GetBool("stdin")
+		if stdin && len(args) > 0 {
+			return fmt.Errorf("IDs are read from stdin with --stdin, got arguments %v", args)
+		}
+		return nil
+	}
+}
+
+// Keys returns the IDs given as arguments, or read from stdin
+func Keys(cmd *cobra.Command, args []string) ([]string, error) {
+	if !readsStdin(cmd, args) {
+		return args, nil
+	}
+	keys, err := ReadKeys(os.Stdin, cmd.Annotations[keyFieldAnnotation])
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no IDs read from stdin")
+	}
+	return keys, nil
+}
+
+// readsStdin reports whether the IDs are read from stdin rather than given as arguments
+func readsStdin(cmd *cobra.Command, args []string) bool {
+	stdin, _ := cmd.Flags().GetBool("stdin")
+	return stdin || (len(args) == 1 && args[0] == "-")
+}
+
+// ReadKeys reads one ID per line, either the ID itself or an NDJSON object with the ID in field.
+// Blank lines are skipped.
+func ReadKeys(r io.Reader, field string) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if !strings.HasPrefix(text, "{") {
+			keys = append(keys, text)
+			continue
+		}
+
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(text), &obj); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+		switch v := obj[field].(type) {
+		case string:
+			if v == "" {
+				return nil, fmt.Errorf("line %d: empty %s", line, field)
+			}
+			keys = append(keys, v)
+		case float64:
+			keys = append(keys, fmt.Sprintf("%.0f", v))
+		default:
+			return nil, fmt.Errorf("line %d: no %s field", line, field)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return keys, nil
+}
+
+// Resolve returns the ID a reference such as a title or library name names, see resolve.Resolve
+func Resolve(ctx context.Context, client *plex.Client, kind resolve.Kind, ref string) (string, error) {
+	return resolve.Resolve(ctx, client, kind, ref, resolve.Options{First: viper.GetBool("first")})
+}
+
+// resolveKey resolves a key given to ForEachKey. Numbers read from stdin come from other commands, so
+// they are taken as IDs without asking the server whether they exist.
+func resolveKey(ctx context.Context, client *plex.Client, kind resolve.Kind, ref string, stdin bool) (string, error) {
+	return resolve.Resolve(ctx, client, kind, ref, resolve.Options{First: viper.GetBool("first"), IDs: stdin})
+}
+
+// ResolveArg resolves the only argument of a command, asking the user to pick one when it was left out
+func ResolveArg(ctx context.Context, client *plex.Client, kind resolve.Kind, args []string) (string, error) {
+	if len(args) > 0 {
+		return Resolve(ctx, client, kind, args[0])
+	}
+	ids, err := resolve.Pick(ctx, client, kind, false)
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// ForEachKey resolves every reference from the arguments or stdin to an ID and runs fn for it, asking
+// the user to pick when there are none. With several references a failure is reported and the rest
+// still run, the returned error then counts the failures. It is a clierr.Partial error when some
+// succeeded, and keeps the kind of the failures when all of them failed the same way. Formats printing
+// the raw data print what fn printed with the options it is given for every ID as one list at the end,
+// lists joined together.
+func ForEachKey(ctx context.Context, client *plex.Client, cmd *cobra.Command, args []string, opts *PlexCtlOptions, fn func(key string, opts *PlexCtlOptions) error) error {
+	keys, err := Keys(cmd, args)
+	if err != nil {
+		return err
+	}
+	kind := resolve.Kind(cmd.Annotations[keyKindAnnotation])
+	if len(keys) == 0 {
+		if keys, err = resolve.Pick(ctx, client, kind, true); err != nil {
+			return err
+		}
+	}
+	stdin := readsStdin(cmd, args)
+	run := func(ref string, opts *PlexCtlOptions) error {
+		key, err := resolveKey(ctx, client, kind, ref, stdin)
+		if err != nil {
+			return err
+		}
+		return fn(key, opts)
+	}
+	if len(keys) == 1 {
+		return run(keys[0], opts)
+	}
+
+	batched := *opts
+	batched.batch = &rawBatch{items: []any{}}
+	var kinds []clierr.Kind
+	for _, key := range keys {
+		if err := run(key, &batched); err != nil {
+			ui.RenderError(fmt.Errorf("%s: %w", key, err))
+			kinds = append(kinds, clierr.KindOf(err))
+		}
+	}
+	if err := batched.batch.print(); err != nil {
+		return err
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+	failure := clierr.Partial
+	if len(kinds) == len(keys) {
+		failure = kinds[0]
+		if slices.ContainsFunc(kinds, func(k clierr.Kind) bool { return k != failure }) {
+			failure = clierr.General
+		}
+	}
+	return clierr.New(failure, "%d of %d items failed", len(kinds), len(keys))
+}
@@ -5,7 +5,12 @@ type PlexCtlOptions struct {
 	OutputFormat string
 	Verbosity    int
 	Sort         string
+	Filter       []string
+	Limit        int
 	Count        int
 	Page         int
 	All          bool
+
+	// batch collects what is printed while ForEachKey runs over several IDs
+	batch *rawBatch
 }
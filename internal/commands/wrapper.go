@@ -2,12 +2,14 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/config"
 	"github.com/ygelfand/plexctl/internal/plex"
 	"github.com/ygelfand/plexctl/internal/presenters"
@@ -25,6 +27,8 @@ func RunWithClient(runner RunnerFunc) func(cmd *cobra.Command, args []string) er
 			OutputFormat: viper.GetString("output"),
 			Verbosity:    viper.GetInt("verbose"),
 			Sort:         viper.GetString("sort"),
+			Filter:       viper.GetStringSlice("filter"),
+			Limit:        viper.GetInt("limit"),
 			Count:        viper.GetInt("count"),
 			Page:         viper.GetInt("page"),
 			All:          viper.GetBool("all"),
@@ -133,29 +137,148 @@ func DiscoverAndSelectServer(ctx context.Context) error {
 	return nil
 }
 
-// Print formats and prints data using the provided Presenter
+// Print formats and prints data using the provided Presenter, after the --sort, --filter and --limit
+// options are applied to its rows
 func Print(p presenters.Presenter, opts *PlexCtlOptions) error {
-	sortCol := opts.Sort
-	if sortCol == "" {
-		sortCol = p.DefaultSort()
+	query, err := rowQuery(p, opts)
+	if err != nil {
+		return err
 	}
 
-	if sortCol != "" {
-		p.SortBy(sortCol)
+	headers, rows, raw := p.Headers(), p.Rows(), p.Raw()
+	if !query.Empty() {
+		// Raw data with one item per row is filtered along with the rows, anything else cannot be
+		items := ui.RawItems(raw, len(rows))
+		paired := items != nil
+		if usesRaw, format := ui.RawFormat(); usesRaw && !paired {
+			return clierr.New(clierr.Usage, "--sort, --filter and --limit cannot be applied to -o %s here, as the data is not a list of rows; use -o table, csv or tsv", format)
+		}
+		rows, items, err = query.Apply(headers, rows, items)
+		if err != nil {
+			return err
+		}
+		if paired {
+			raw = append([]any{}, items...)
+		}
 	}
 
-	title := p.Title()
-	// Suppress title for machine-readable formats
-	if opts.OutputFormat != "table" && opts.OutputFormat != "text" && opts.OutputFormat != "txt" && opts.OutputFormat != "" {
-		title = ""
+	if opts.batch != nil {
+		if usesRaw, _ := ui.RawFormat(); usesRaw {
+			opts.batch.items = append(opts.batch.items, ui.RawList(raw)...)
+			opts.batch.printed = true
+			return nil
+		}
 	}
 
 	data := ui.OutputData{
-		Title:   title,
-		Headers: p.Headers(),
-		Rows:    p.Rows(),
-		Raw:     p.Raw(),
+		Title:   title(p, opts),
+		Headers: headers,
+		Rows:    rows,
+		Raw:     raw,
 	}
 
 	return data.Print()
 }
+
+// PrintDone reports an action with nothing to list: the message, or raw describing what was done for
+// formats printing the raw data
+func PrintDone(msg string, raw any, opts *PlexCtlOptions) error {
+	if usesRaw, _ := ui.RawFormat(); usesRaw {
+		return Print(presenters.SimplePresenter{RawData: raw}, opts)
+	}
+	ui.RenderSuccess(msg)
+	return nil
+}
+
+// rawBatch collects what is printed for each ID while ForEachKey runs over several, so that formats
+// printing the raw data print a single list rather than one document per ID
+type rawBatch struct {
+	items   []any
+	printed bool // something was printed, if only an empty list
+}
+
+// print prints the items collected for every ID as one list, unless nothing was printed at all
+func (b *rawBatch) print() error {
+	if !b.printed {
+		return nil
+	}
+	return ui.OutputData{Raw: b.items}.Print()
+}
+
+// errLimitReached stops a stream once --limit rows have been printed
+var errLimitReached = errors.New("limit reached")
+
+// PrintStream prints a StreamingPresenter page by page as it is fetched. When a sort is requested,
+// or the output format needs all of the data at once, every page is collected and printed with Print.
+// When there are no items, only formats printing the raw data print anything, an empty list.
+func PrintStream(ctx context.Context, p presenters.StreamingPresenter, opts *PlexCtlOptions) error {
+	if opts.Sort == "" && p.DefaultSort() == "" && opts.batch == nil {
+		if w, ok := ui.NewStreamWriter(title(p, opts), p.Headers()); ok {
+			query, err := rowQuery(p, opts)
+			if err != nil {
+				return err
+			}
+			printed := 0
+			err = p.Stream(ctx, func(rows [][]string, raw []any) error {
+				rows, raw, err := query.Filter(p.Headers(), rows, raw)
+				if err != nil {
+					return err
+				}
+				if query.Limit > 0 && printed+len(rows) >= query.Limit {
+					n := query.Limit - printed
+					if err := w.WritePage(rows[:n], raw[:min(n, len(raw))]); err != nil {
+						return err
+					}
+					return errLimitReached
+				}
+				printed += len(rows)
+				return w.WritePage(rows, raw)
+			})
+			if err != nil && !errors.Is(err, errLimitReached) {
+				w.Close()
+				return err
+			}
+			return w.Close()
+		}
+	}
+
+	if err := p.Collect(ctx); err != nil {
+		return err
+	}
+	if usesRaw, _ := ui.RawFormat(); !usesRaw && len(p.Rows()) == 0 {
+		return nil
+	}
+	return Print(p, opts)
+}
+
+// rowQuery sorts the presenter itself when --sort is a single column it knows, otherwise by its default,
+// and returns the rest of --sort, --filter and --limit to apply to its rows
+func rowQuery(p presenters.Presenter, opts *PlexCtlOptions) (presenters.RowQuery, error) {
+	keys, plain, err := presenters.ParseSort(opts.Sort)
+	if err != nil {
+		return presenters.RowQuery{}, err
+	}
+	filters, err := presenters.ParseFilters(opts.Filter)
+	if err != nil {
+		return presenters.RowQuery{}, err
+	}
+
+	query := presenters.RowQuery{Filters: filters, Limit: opts.Limit}
+	// The presenter compares its own columns by value, e.g. durations and dates, rather than as text
+	if plain && p.SortBy(keys[0].Column) {
+		return query, nil
+	}
+	if d := p.DefaultSort(); d != "" {
+		p.SortBy(d)
+	}
+	query.Sort = keys
+	return query, nil
+}
+
+// title suppresses the title for machine-readable formats
+func title(p presenters.Presenter, opts *PlexCtlOptions) string {
+	if !ui.TitledFormat(opts.OutputFormat) {
+		return ""
+	}
+	return p.Title()
+}
@@ -97,6 +97,29 @@ type HomeUser struct {
 	AccessToken string `mapstructure:"access_token" yaml:"access_token"` // Server-specific Access Token
 }
 
+// HTTPConfig tunes how requests to a server are sent. Limits apply to each server separately.
+type HTTPConfig struct {
+	TimeoutSeconds int `mapstructure:"timeout_seconds" yaml:"timeout_seconds"`   // per attempt
+	Retries        int `mapstructure:"retries" yaml:"retries"`                   // for idempotent requests failing with 5xx or a timeout
+	RetryBackoffMS int `mapstructure:"retry_backoff_ms" yaml:"retry_backoff_ms"` // first delay, doubled for each retry
+	MaxConcurrent  int `mapstructure:"max_concurrent" yaml:"max_concurrent"`     // requests in flight, 0 for no limit
+	RateLimit      int `mapstructure:"rate_limit" yaml:"rate_limit"`             // requests per second, 0 for no limit
+
+	// The breaker fails requests fast for a while after this many consecutive failures, 0 disables it
+	BreakerThreshold       int `mapstructure:"breaker_threshold" yaml:"breaker_threshold"`
+	BreakerCooldownSeconds int `mapstructure:"breaker_cooldown_seconds" yaml:"breaker_cooldown_seconds"`
+}
+
+// Context bundles account and presentation settings that are switched together.
+// Empty fields inherit the top-level value.
+type Context struct {
+	Token         string   `mapstructure:"token" yaml:"token,omitempty"`
+	HomeUser      HomeUser `mapstructure:"home_user" yaml:"home_user,omitempty"`
+	DefaultServer string   `mapstructure:"default_server" yaml:"default_server,omitempty"`
+	Theme         string   `mapstructure:"theme" yaml:"theme,omitempty"`
+	OutputFormat  string   `mapstructure:"output" yaml:"output,omitempty"`
+}
+
 // Config holds the global configuration for plexctl
 type Config struct {
 	// Global settings
@@ -110,19 +133,32 @@ type Config struct {
 	DefaultViewMode   ViewMode          `mapstructure:"default_view_mode"`   // list, poster
 	CacheDir          string            `mapstructure:"cache_dir"`
 	NoCache           bool              `mapstructure:"no_cache"`
+	CacheMaxSizeMB    int               `mapstructure:"cache_max_size_mb"`
 	DefaultToTui      bool              `mapstructure:"default_to_tui"`
 	AutoHomeLogin     bool              `mapstructure:"auto_home_login"`
 	CloseVideoOnQuit  bool              `mapstructure:"close_video_on_quit"`
+	HTTP              HTTPConfig        `mapstructure:"http"`
 
 	// Server management
 	DefaultServer string            `mapstructure:"default_server"` // Stores the ClientIdentifier
 	Servers       map[string]Server `mapstructure:"servers"`        // Key is ClientIdentifier
 
+	// Context management
+	CurrentContext string             `mapstructure:"current_context"`
+	Contexts       map[string]Context `mapstructure:"contexts"` // Key is context name
+
 	// Runtime only
-	ConfigPath string       `mapstructure:"-"`
-	LogFile    string       `mapstructure:"-"`
-	Logger     *slog.Logger `mapstructure:"-"`
-	LogLevel   *slog.LevelVar
+	ConfigPath    string       `mapstructure:"-"`
+	LogFile       string       `mapstructure:"-"`
+	Logger        *slog.Logger `mapstructure:"-"`
+	LogLevel      *slog.LevelVar
+	ActiveContext string `mapstructure:"-"`
+
+	// top-level values shadowed by the active context
+	global Context
+
+	// values set by flags and the environment for this run only
+	overrides []override
 }
 
 var (
@@ -138,18 +174,11 @@ const (
 // Get returns the global configuration singleton
 func Get() *Config {
 	once.Do(func() {
-		home, _ := os.UserHomeDir()
 		lvl := &slog.LevelVar{}
 		lvl.Set(slog.LevelInfo)
-		instance = &Config{
-			Logger:          slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})),
-			LogLevel:        lvl,
-			Servers:         make(map[string]Server),
-			CacheDir:        filepath.Join(home, ".plexctl", "cache"),
-			DefaultToTui:    true,
-			AutoHomeLogin:   true,
-			DefaultViewMode: ViewModePoster,
-		}
+		instance = Defaults()
+		instance.Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl}))
+		instance.LogLevel = lvl
 	})
 	return instance
 }
@@ -202,32 +231,43 @@ func (c *Config) Enabled(level slog.Level) bool {
 
 // Save persists the current configuration to disk
 func (c *Config) Save() error {
-	// Sync struct fields to viper before writing
-	viper.Set("token", c.Token)
-	viper.Set("home_user", c.HomeUser)
-	viper.Set("output", c.OutputFormat)
-	viper.Set("verbose", c.Verbosity)
-	viper.Set("theme", c.Theme)
-	viper.Set("icon_type", c.IconType)
-	viper.Set("library_name_format", c.LibraryNameFormat)
-	viper.Set("default_view_mode", c.DefaultViewMode)
-	viper.Set("default_to_tui", c.DefaultToTui)
-	viper.Set("auto_home_login", c.AutoHomeLogin)
-	viper.Set("close_video_on_quit", c.CloseVideoOnQuit)
-	viper.Set("cache_dir", c.CacheDir)
-	viper.Set("default_server", c.DefaultServer)
-	viper.Set("servers", c.Servers)
-
-	if c.ConfigPath != "" {
-		return viper.WriteConfig()
+	// Values set for this run only by flags and the environment are not written
+	defer c.withoutOverrides()()
+
+	// Values the active context overrides are written back to it, the others to the top level
+	global := c.snapshot()
+	if c.ActiveContext != "" {
+		c.Contexts[c.ActiveContext], c.global = c.splitContext(c.Contexts[c.ActiveContext])
+		global = c.global
 	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
+	// A fresh viper writes only the settings, not the flags bound to the global one
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set("token", global.Token)
+	v.Set("home_user", global.HomeUser)
+	v.Set("output", global.OutputFormat)
+	v.Set("verbose", c.Verbosity)
+	v.Set("theme", global.Theme)
+	v.Set("icon_type", c.IconType)
+	v.Set("library_name_format", c.LibraryNameFormat)
+	v.Set("default_view_mode", c.DefaultViewMode)
+	v.Set("default_to_tui", c.DefaultToTui)
+	v.Set("auto_home_login", c.AutoHomeLogin)
+	v.Set("close_video_on_quit", c.CloseVideoOnQuit)
+	v.Set("cache_dir", c.CacheDir)
+	v.Set("no_cache", c.NoCache)
+	v.Set("cache_max_size_mb", c.CacheMaxSizeMB)
+	v.Set("http", c.HTTP)
+	v.Set("default_server", global.DefaultServer)
+	v.Set("servers", c.Servers)
+	v.Set("current_context", c.CurrentContext)
+	v.Set("contexts", c.Contexts)
+
+	if c.ConfigPath == "" {
+		c.ConfigPath = DefaultConfigPath()
 	}
-	c.ConfigPath = filepath.Join(home, ".plexctl.yaml")
-	return viper.WriteConfigAs(c.ConfigPath)
+	return v.WriteConfigAs(c.ConfigPath)
 }
 
 // GetActiveServer returns the configuration for the currently selected server.
@@ -243,21 +283,29 @@ func (c *Config) GetActiveServer() (string, Server, bool) {
 
 // SetDefaultServer sets the default server by ID or Name
 func (c *Config) SetDefaultServer(idOrName string) error {
+	id, err := c.ResolveServerID(idOrName)
+	if err != nil {
+		return err
+	}
+	c.DefaultServer = id
+	return nil
+}
+
+// ResolveServerID returns the ClientIdentifier of a configured server given its ID or Name
+func (c *Config) ResolveServerID(idOrName string) (string, error) {
 	// Try ID first
 	if _, ok := c.Servers[idOrName]; ok {
-		c.DefaultServer = idOrName
-		return nil
+		return idOrName, nil
 	}
 
 	// Try Name
 	for id, srv := range c.Servers {
 		if srv.Name == idOrName {
-			c.DefaultServer = id
-			return nil
+			return id, nil
 		}
 	}
 
-	return fmt.Errorf("server '%s' not found in configuration", idOrName)
+	return "", fmt.Errorf("server '%s' not found in configuration", idOrName)
 }
 
 // AddServer adds or updates a server configuration and optionally sets it as default
@@ -267,3 +315,106 @@ func (c *Config) AddServer(id string, config Server, setAsDefault bool) {
 		c.DefaultServer = id
 	}
 }
+
+// UseContext overlays the named context on top of the top-level settings for this process.
+// An empty name leaves the top-level settings active.
+func (c *Config) UseContext(name string) error {
+	if name == "" {
+		return nil
+	}
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		return fmt.Errorf("context '%s' not found in configuration", name)
+	}
+
+	if c.ActiveContext == "" {
+		c.global = c.snapshot()
+	}
+	c.restore(c.global)
+	c.ActiveContext = name
+
+	// The home user belongs to the account, so it follows the token
+	if ctx.Token != "" {
+		c.Token = ctx.Token
+		c.HomeUser = ctx.HomeUser
+	}
+	if ctx.DefaultServer != "" {
+		c.DefaultServer = ctx.DefaultServer
+	}
+	if ctx.Theme != "" {
+		c.Theme = ctx.Theme
+	}
+	if ctx.OutputFormat != "" {
+		c.OutputFormat = ctx.OutputFormat
+	}
+	return nil
+}
+
+// SetContext creates or replaces a context, re-applying it if it is the active one
+func (c *Config) SetContext(name string, ctx Context) error {
+	if c.Contexts == nil {
+		c.Contexts = make(map[string]Context)
+	}
+	c.Contexts[name] = ctx
+	if c.ActiveContext == name {
+		return c.UseContext(name)
+	}
+	return nil
+}
+
+// DeleteContext removes a context. The active or current context cannot be deleted.
+func (c *Config) DeleteContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return fmt.Errorf("context '%s' not found in configuration", name)
+	}
+	if name == c.ActiveContext || name == c.CurrentContext {
+		return fmt.Errorf("context '%s' is in use, switch to another context first", name)
+	}
+	delete(c.Contexts, name)
+	return nil
+}
+
+func (c *Config) snapshot() Context {
+	return Context{
+		Token:         c.Token,
+		HomeUser:      c.HomeUser,
+		DefaultServer: c.DefaultServer,
+		Theme:         c.Theme,
+		OutputFormat:  c.OutputFormat,
+	}
+}
+
+func (c *Config) restore(ctx Context) {
+	c.Token = ctx.Token
+	c.HomeUser = ctx.HomeUser
+	c.DefaultServer = ctx.DefaultServer
+	c.Theme = ctx.Theme
+	c.OutputFormat = ctx.OutputFormat
+}
+
+// splitContext sorts the runtime values between ctx and the top-level values: ctx gets the fields it
+// already overrides, and the top level everything else
+func (c *Config) splitContext(ctx Context) (Context, Context) {
+	global := c.global
+	if ctx.Token != "" {
+		ctx.Token, ctx.HomeUser = c.Token, c.HomeUser
+	} else {
+		global.Token, global.HomeUser = c.Token, c.HomeUser
+	}
+	if ctx.DefaultServer != "" {
+		ctx.DefaultServer = c.DefaultServer
+	} else {
+		global.DefaultServer = c.DefaultServer
+	}
+	if ctx.Theme != "" {
+		ctx.Theme = c.Theme
+	} else {
+		global.Theme = c.Theme
+	}
+	if ctx.OutputFormat != "" {
+		ctx.OutputFormat = c.OutputFormat
+	} else {
+		global.OutputFormat = c.OutputFormat
+	}
+	return ctx, global
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// override is a value set for this run only, by a flag or the environment, and the value it replaced
+type override struct {
+	key       string
+	value     any
+	persisted any
+}
+
+// MarkOverrides records that the given top-level keys, and every one set through a PLEXCTL_ environment
+// variable, were set for this run only. Save writes the values from the file for them instead, unless
+// they were changed since.
+func (c *Config) MarkOverrides(keys ...string) {
+	t := reflect.TypeOf(*c)
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if _, ok := os.LookupEnv("PLEXCTL_" + strings.ToUpper(tag)); ok {
+			keys = append(keys, tag)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	file := Defaults()
+	if c.ConfigPath != "" {
+		if loaded, err := LoadFile(c.ConfigPath); err == nil {
+			file = loaded
+		}
+	}
+	for _, key := range keys {
+		value, err := c.GetValue(key)
+		if err != nil {
+			continue
+		}
+		persisted, err := file.GetValue(key)
+		if err != nil {
+			continue
+		}
+		c.overrides = append(c.overrides, override{key: key, value: value, persisted: persisted})
+	}
+}
+
+// Override sets a top-level value for this run only, Save keeps the value it replaces
+func (c *Config) Override(key string, value any) error {
+	persisted, err := c.GetValue(key)
+	if err != nil {
+		return err
+	}
+	err = update(reflect.ValueOf(c).Elem(), splitKey(key), key, func(v reflect.Value) error {
+		v.Set(reflect.ValueOf(value).Convert(v.Type()))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.overrides = append(c.overrides, override{key: key, value: value, persisted: persisted})
+	return nil
+}
+
+// withoutOverrides puts back the values overrides replaced, in the settings and in the top-level values
+// shadowed by the active context, and returns a function applying them again
+func (c *Config) withoutOverrides() func() {
+	var undo []func()
+	swap := func(v reflect.Value, o override) {
+		if !v.IsValid() || !reflect.DeepEqual(v.Interface(), reflect.ValueOf(o.value).Convert(v.Type()).Interface()) {
+			return
+		}
+		current := reflect.ValueOf(v.Interface())
+		v.Set(reflect.ValueOf(o.persisted).Convert(v.Type()))
+		undo = append(undo, func() { v.Set(current) })
+	}
+
+	for _, o := range c.overrides {
+		field, ok := fieldByTag(reflect.ValueOf(c).Elem(), o.key)
+		if !ok {
+			continue
+		}
+		swap(field, o)
+		if c.ActiveContext != "" {
+			if global, ok := fieldByTag(reflect.ValueOf(&c.global).Elem(), o.key); ok {
+				swap(global, o)
+			}
+		}
+	}
+	return func() {
+		for _, fn := range undo {
+			fn()
+		}
+	}
+}
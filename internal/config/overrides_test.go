@@ -0,0 +1,95 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveSkipsOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plexctl.yaml")
+	c := Defaults()
+	c.ConfigPath = path
+	c.OutputFormat = "table"
+	c.Contexts["work"] = Context{Theme: "nord"}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PLEXCTL_NO_CACHE", "true")
+	c.NoCache = true
+	c.MarkOverrides()
+	if err := c.UseContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Override("output", "json"); err != nil {
+		t.Fatal(err)
+	}
+	c.Theme = "dracula"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.NoCache {
+		t.Error("no_cache from the environment was saved")
+	}
+	if saved.OutputFormat != "table" || saved.Contexts["work"].OutputFormat != "" {
+		t.Errorf("output override was saved: top-level %q, context %q", saved.OutputFormat, saved.Contexts["work"].OutputFormat)
+	}
+	if saved.Contexts["work"].Theme != "dracula" {
+		t.Errorf("context theme = %q, want dracula", saved.Contexts["work"].Theme)
+	}
+
+	// The overrides still apply for the rest of the run
+	if !c.NoCache || c.OutputFormat != "json" {
+		t.Errorf("overrides lost after saving: no_cache %v, output %q", c.NoCache, c.OutputFormat)
+	}
+}
+
+func TestSaveKeepsChangedOverride(t *testing.T) {
+	c := Defaults()
+	c.ConfigPath = filepath.Join(t.TempDir(), "plexctl.yaml")
+	if err := c.Override("output", "json"); err != nil {
+		t.Fatal(err)
+	}
+	c.OutputFormat = "yaml"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadFile(c.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.OutputFormat != "yaml" {
+		t.Errorf("output = %q, want the value set during the run", saved.OutputFormat)
+	}
+}
+
+func TestSaveLeavesContextOwnSettings(t *testing.T) {
+	c := Defaults()
+	c.ConfigPath = filepath.Join(t.TempDir(), "plexctl.yaml")
+	c.Theme = "nord"
+	c.Contexts["work"] = Context{OutputFormat: "yaml"}
+	if err := c.UseContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	c.Theme = "dracula"
+	c.OutputFormat = "json"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := LoadFile(c.ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Theme != "dracula" || saved.Contexts["work"].Theme != "" {
+		t.Errorf("theme not overridden by the context: top-level %q, context %q", saved.Theme, saved.Contexts["work"].Theme)
+	}
+	if saved.OutputFormat == "json" || saved.Contexts["work"].OutputFormat != "json" {
+		t.Errorf("output overridden by the context: top-level %q, context %q", saved.OutputFormat, saved.Contexts["work"].OutputFormat)
+	}
+}
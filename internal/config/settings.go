@@ -0,0 +1,418 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Setting is a single leaf value of the configuration addressed by its dotted key
+type Setting struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  any    `json:"value" yaml:"value"`
+	Type   string `json:"type" yaml:"type"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// Value sources reported by Source
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceContext = "context"
+	SourceFile    = "file"
+	SourceDefault = "default"
+)
+
+// DefaultCacheMaxSizeMB bounds the disk cache when cache_max_size_mb is not configured
+const DefaultCacheMaxSizeMB = 1024
+
+// DefaultHTTP is the request policy used for settings missing from the http section
+var DefaultHTTP = HTTPConfig{
+	TimeoutSeconds:         60,
+	Retries:                3,
+	RetryBackoffMS:         500,
+	MaxConcurrent:          8,
+	BreakerThreshold:       5,
+	BreakerCooldownSeconds: 30,
+}
+
+// enumValues lists the accepted values for enum-like setting types
+var enumValues = map[reflect.Type][]string{
+	reflect.TypeOf(IconType("")):          {string(IconTypeASCII), string(IconTypeEmoji), string(IconTypeNerdFonts)},
+	reflect.TypeOf(LibraryNameFormat("")): {string(LibraryNameFormatIconOnly), string(LibraryNameFormatIconName), string(LibraryNameFormatNameIcon), string(LibraryNameFormatName)},
+	reflect.TypeOf(ViewMode("")):          {string(ViewModeList), string(ViewModePoster)},
+}
+
+// contextKeys are the top-level keys a context can override
+var contextKeys = []string{"token", "home_user", "default_server", "theme", "output"}
+
+// Defaults returns a configuration populated with the built-in default values
+func Defaults() *Config {
+	home, _ := os.UserHomeDir()
+	return &Config{
+		Servers:         make(map[string]Server),
+		Contexts:        make(map[string]Context),
+		CacheDir:        filepath.Join(home, ".plexctl", "cache"),
+		CacheMaxSizeMB:  DefaultCacheMaxSizeMB,
+		DefaultToTui:    true,
+		AutoHomeLogin:   true,
+		DefaultViewMode: ViewModePoster,
+		HTTP:            DefaultHTTP,
+	}
+}
+
+// EnumValues returns the accepted values for the setting at key, or nil if it is not an enum
+func (c *Config) EnumValues(key string) []string {
+	v, err := lookup(reflect.ValueOf(c).Elem(), splitKey(key))
+	if err != nil {
+		return nil
+	}
+	return enumValues[v.Type()]
+}
+
+// Settings flattens the configuration into a sorted list of leaf settings
+func (c *Config) Settings() []Setting {
+	var res []Setting
+	walk(reflect.ValueOf(c).Elem(), "", func(key string, v reflect.Value) {
+		res = append(res, Setting{
+			Key:    key,
+			Value:  v.Interface(),
+			Type:   typeName(v.Type()),
+			Source: c.Source(key),
+		})
+	})
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+// GetValue returns the value stored at the dotted key
+func (c *Config) GetValue(key string) (any, error) {
+	v, err := lookup(reflect.ValueOf(c).Elem(), splitKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// SetValue parses raw according to the type of the setting at key and stores it
+func (c *Config) SetValue(key, raw string) error {
+	return update(reflect.ValueOf(c).Elem(), splitKey(key), key, func(v reflect.Value) error {
+		return parseInto(v, raw)
+	})
+}
+
+// UnsetValue restores the built-in default for key, or removes it if it is a map entry
+func (c *Config) UnsetValue(key string) error {
+	parts := splitKey(key)
+	root := reflect.ValueOf(c).Elem()
+	target, err := lookup(root, parts)
+	if err != nil {
+		return err
+	}
+	if target.Kind() == reflect.Struct || target.Kind() == reflect.Map {
+		return remove(root, parts, key)
+	}
+
+	def, err := lookup(reflect.ValueOf(Defaults()).Elem(), parts)
+	return update(root, parts, key, func(v reflect.Value) error {
+		if err == nil {
+			v.Set(def)
+		} else {
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	})
+}
+
+// Source reports where the effective value of a top-level key comes from
+func (c *Config) Source(key string) string {
+	top := splitKey(key)[0]
+	if c.ActiveContext != "" && slices.Contains(contextKeys, top) {
+		if ctx, ok := c.Contexts[c.ActiveContext]; ok && contextOverrides(ctx, top) {
+			return SourceContext
+		}
+	}
+	if _, ok := os.LookupEnv("PLEXCTL_" + strings.ToUpper(top)); ok && viper.IsSet(top) {
+		return SourceEnv
+	}
+	if viper.InConfig(top) {
+		return SourceFile
+	}
+	return SourceDefault
+}
+
+// Validate checks enum values and cross references, returning every problem found
+func (c *Config) Validate() []error {
+	var errs []error
+	walk(reflect.ValueOf(c).Elem(), "", func(key string, v reflect.Value) {
+		allowed, ok := enumValues[v.Type()]
+		if ok && v.String() != "" && !slices.Contains(allowed, v.String()) {
+			errs = append(errs, fmt.Errorf("%s: invalid value %q (allowed: %s)", key, v.String(), strings.Join(allowed, ", ")))
+		}
+	})
+
+	walk(reflect.ValueOf(&c.HTTP).Elem(), "http", func(key string, v reflect.Value) {
+		if v.Int() < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", key))
+		}
+	})
+
+	if c.DefaultServer != "" {
+		if _, ok := c.Servers[c.DefaultServer]; !ok {
+			errs = append(errs, fmt.Errorf("default_server: server '%s' is not configured", c.DefaultServer))
+		}
+	}
+	for id, srv := range c.Servers {
+		if srv.URL == "" {
+			errs = append(errs, fmt.Errorf("servers.%s.url: must not be empty", id))
+		}
+	}
+	if c.CurrentContext != "" {
+		if _, ok := c.Contexts[c.CurrentContext]; !ok {
+			errs = append(errs, fmt.Errorf("current_context: context '%s' is not configured", c.CurrentContext))
+		}
+	}
+	for name, ctx := range c.Contexts {
+		if ctx.DefaultServer != "" {
+			if _, ok := c.Servers[ctx.DefaultServer]; !ok {
+				errs = append(errs, fmt.Errorf("contexts.%s.default_server: server '%s' is not configured", name, ctx.DefaultServer))
+			}
+		}
+	}
+	return errs
+}
+
+func contextOverrides(ctx Context, key string) bool {
+	switch key {
+	case "token", "home_user":
+		return ctx.Token != ""
+	case "default_server":
+		return ctx.DefaultServer != ""
+	case "theme":
+		return ctx.Theme != ""
+	case "output":
+		return ctx.OutputFormat != ""
+	}
+	return false
+}
+
+func splitKey(key string) []string {
+	return strings.Split(strings.Trim(key, "."), ".")
+}
+
+// fieldByTag finds the struct field whose mapstructure tag matches name
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if !f.IsExported() || tag == "" || tag == "-" {
+			continue
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func lookup(v reflect.Value, parts []string) (reflect.Value, error) {
+	for i, p := range parts {
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByTag(v, p)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("unknown setting '%s'", strings.Join(parts[:i+1], "."))
+			}
+			v = f
+		case reflect.Map:
+			e := v.MapIndex(reflect.ValueOf(p))
+			if !e.IsValid() {
+				return reflect.Value{}, fmt.Errorf("'%s' is not set", strings.Join(parts[:i+1], "."))
+			}
+			v = e
+		default:
+			return reflect.Value{}, fmt.Errorf("'%s' has no nested settings", strings.Join(parts[:i], "."))
+		}
+	}
+	return v, nil
+}
+
+// update walks to the value at parts and applies fn. Map entries are copied, updated and stored back.
+func update(v reflect.Value, parts []string, key string, fn func(reflect.Value) error) error {
+	if len(parts) == 0 {
+		if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+			return fmt.Errorf("'%s' is a section, set one of its keys instead", key)
+		}
+		return fn(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ok := fieldByTag(v, parts[0])
+		if !ok {
+			return fmt.Errorf("unknown setting '%s'", key)
+		}
+		return update(f, parts[1:], key, fn)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		mk := reflect.ValueOf(parts[0])
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(mk); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := update(elem, parts[1:], key, fn); err != nil {
+			return err
+		}
+		v.SetMapIndex(mk, elem)
+		return nil
+	default:
+		return fmt.Errorf("unknown setting '%s'", key)
+	}
+}
+
+// remove deletes the map entry addressed by parts, or resets a struct section to its zero value
+func remove(v reflect.Value, parts []string, key string) error {
+	if len(parts) == 1 && v.Kind() == reflect.Map {
+		v.SetMapIndex(reflect.ValueOf(parts[0]), reflect.Value{})
+		return nil
+	}
+	if len(parts) == 0 {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ok := fieldByTag(v, parts[0])
+		if !ok {
+			return fmt.Errorf("unknown setting '%s'", key)
+		}
+		return remove(f, parts[1:], key)
+	case reflect.Map:
+		mk := reflect.ValueOf(parts[0])
+		existing := v.MapIndex(mk)
+		if !existing.IsValid() {
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		elem.Set(existing)
+		if err := remove(elem, parts[1:], key); err != nil {
+			return err
+		}
+		v.SetMapIndex(mk, elem)
+		return nil
+	default:
+		return fmt.Errorf("unknown setting '%s'", key)
+	}
+}
+
+func parseInto(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if allowed, ok := enumValues[v.Type()]; ok && raw != "" && !slices.Contains(allowed, raw) {
+			return fmt.Errorf("invalid value %q (allowed: %s)", raw, strings.Join(allowed, ", "))
+		}
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", v.Type())
+		}
+		var items []string
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				items = append(items, s)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+func walk(v reflect.Value, prefix string, visit func(key string, v reflect.Value)) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+			if !f.IsExported() || tag == "" || tag == "-" {
+				continue
+			}
+			walk(v.Field(i), join(tag), visit)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			walk(v.MapIndex(k), join(k.String()), visit)
+		}
+	default:
+		visit(prefix, v)
+	}
+}
+
+func typeName(t reflect.Type) string {
+	if _, ok := enumValues[t]; ok {
+		return "enum"
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "list"
+	case reflect.Int, reflect.Int64:
+		return "int"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// LoadFile reads and decodes a configuration file without applying flags, env or contexts
+func LoadFile(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	c := Defaults()
+	if err := v.Unmarshal(c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// DefaultConfigPath returns the path used when no config file exists yet
+func DefaultConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".plexctl.yaml")
+}
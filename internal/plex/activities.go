@@ -0,0 +1,96 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// ActivityPollInterval is how often running activities are polled while waiting on them
+const ActivityPollInterval = time.Second
+
+// ActivityStartTimeout bounds how long to wait for an activity to appear after starting work
+const ActivityStartTimeout = 15 * time.Second
+
+// ListActivities returns the activities currently running on the server
+func ListActivities(ctx context.Context, client *Client) ([]operations.Activity, error) {
+	slog.Debug("SDK: Listing activities")
+	res, err := client.SDK.Activities.ListActivities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activities: %w", err)
+	}
+	if res.Object == nil || res.Object.MediaContainer == nil {
+		return nil, nil
+	}
+	return res.Object.MediaContainer.Activity, nil
+}
+
+// ActivityIDs returns the UUIDs of the given activities
+func ActivityIDs(activities []operations.Activity) []string {
+	ids := make([]string, 0, len(activities))
+	for _, a := range activities {
+		if id := ui.PtrToString(a.UUID); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// WaitForActivities waits for work started after the existing activities were snapshotted to finish.
+// It first waits up to ActivityStartTimeout for new activities to appear, then polls until they are
+// all gone, calling onUpdate with the ones still running. It returns false if no activity appeared.
+func WaitForActivities(ctx context.Context, client *Client, existing []string, onUpdate func([]operations.Activity)) (bool, error) {
+	var tracked []string
+	deadline := time.Now().Add(ActivityStartTimeout)
+
+	ticker := time.NewTicker(ActivityPollInterval)
+	defer ticker.Stop()
+
+	for {
+		activities, err := ListActivities(ctx, client)
+		if err != nil {
+			return len(tracked) > 0, err
+		}
+
+		var running []operations.Activity
+		for _, a := range activities {
+			id := ui.PtrToString(a.UUID)
+			if slices.Contains(existing, id) {
+				continue
+			}
+			if !slices.Contains(tracked, id) {
+				slog.Debug("SDK: Tracking activity", "uuid", id, "type", ui.PtrToString(a.Type))
+				tracked = append(tracked, id)
+			}
+			running = append(running, a)
+		}
+
+		if len(tracked) > 0 {
+			if len(running) == 0 {
+				return true, nil
+			}
+			onUpdate(running)
+		} else if time.Now().After(deadline) {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return len(tracked) > 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ActivityProgress returns an activity's progress as a fraction, or false when it is indeterminate
+func ActivityProgress(a operations.Activity) (float64, bool) {
+	if a.Progress == nil || *a.Progress < 0 {
+		return 0, false
+	}
+	return min(*a.Progress, 100) / 100, true
+}
@@ -0,0 +1,271 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// Audit checks
+const (
+	AuditDuplicate       = "duplicate"
+	AuditMissingPoster   = "missing-poster"
+	AuditMissingSummary  = "missing-summary"
+	AuditUnmatched       = "unmatched"
+	AuditLowResolution   = "low-resolution"
+	AuditMissingEpisodes = "missing-episodes"
+)
+
+// AuditChecks lists every check in the order findings are reported
+var AuditChecks = []string{AuditDuplicate, AuditMissingPoster, AuditMissingSummary, AuditUnmatched, AuditLowResolution, AuditMissingEpisodes}
+
+// LeafTypes are the server type numbers of the playable items in each kind of library
+var LeafTypes = map[components.MediaTypeString]string{
+	components.MediaTypeStringMovie:  "1",
+	components.MediaTypeStringTvShow: "4",
+	components.MediaTypeStringArtist: "10",
+	components.MediaTypeStringPhoto:  "13",
+}
+
+// AuditFinding is a single problem found in a library
+type AuditFinding struct {
+	Check     string `json:"check"`
+	RatingKey string `json:"rating_key"`
+	Title     string `json:"title"`
+	Library   string `json:"library"`
+	Detail    string `json:"detail"`
+}
+
+// AuditSection is a library section to audit
+type AuditSection struct {
+	ID    string
+	Title string
+	Type  components.MediaTypeString
+}
+
+// AuditOptions select the checks to run
+type AuditOptions struct {
+	Checks        []string // defaults to all AuditChecks
+	MinResolution int      // vertical resolution below which files are reported, e.g. 720
+}
+
+func (o AuditOptions) enabled(check string) bool {
+	return len(o.Checks) == 0 || slices.Contains(o.Checks, check)
+}
+
+type auditor struct {
+	opts     AuditOptions
+	findings []AuditFinding
+	titles   map[string][]AuditFinding // items by normalized title and year, for duplicates across sections
+}
+
+// Audit walks the given movie and show sections and reports problems found in their items
+func Audit(ctx context.Context, client *Client, sections []AuditSection, opts AuditOptions) ([]AuditFinding, error) {
+	a := &auditor{opts: opts, titles: make(map[string][]AuditFinding)}
+
+	for _, section := range sections {
+		if section.Type != components.MediaTypeStringMovie && section.Type != components.MediaTypeStringTvShow {
+			slog.Debug("Audit: Skipping library", "library_id", section.ID, "type", section.Type)
+			continue
+		}
+
+		slog.Debug("Audit: Walking library", "library_id", section.ID)
+		items, err := WalkContent(ctx, true, 1, 0, LibraryWalker(client, section.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list library %s: %w", section.ID, err)
+		}
+		for _, meta := range items {
+			a.checkItem(section, meta)
+		}
+
+		if section.Type == components.MediaTypeStringTvShow &&
+			(opts.enabled(AuditDuplicate) || opts.enabled(AuditLowResolution) || opts.enabled(AuditMissingEpisodes)) {
+			episodes, err := WalkContent(ctx, true, 1, 0, FilteredLibraryWalker(section.ID, url.Values{"type": {LeafTypes[section.Type]}}))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list episodes of library %s: %w", section.ID, err)
+			}
+			a.checkEpisodes(section, episodes)
+		}
+	}
+
+	a.checkTitles()
+
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		return slices.Index(AuditChecks, a.findings[i].Check) < slices.Index(AuditChecks, a.findings[j].Check)
+	})
+	return a.findings, nil
+}
+
+func (a *auditor) add(check string, section AuditSection, meta components.Metadata, detail string) {
+	if !a.opts.enabled(check) {
+		return
+	}
+	a.findings = append(a.findings, AuditFinding{
+		Check:     check,
+		RatingKey: ui.PtrToString(meta.RatingKey),
+		Title:     auditTitle(meta),
+		Library:   section.Title,
+		Detail:    detail,
+	})
+}
+
+// checkItem runs the checks that apply to a top level movie or show
+func (a *auditor) checkItem(section AuditSection, meta components.Metadata) {
+	if ui.PtrToString(meta.Thumb) == "" {
+		a.add(AuditMissingPoster, section, meta, "no poster")
+	}
+	if strings.TrimSpace(ui.PtrToString(meta.Summary)) == "" {
+		a.add(AuditMissingSummary, section, meta, "no summary")
+	}
+	if guid := ui.PtrToString(meta.GUID); guid == "" || strings.HasPrefix(guid, "local://") || strings.Contains(guid, "agents.none") {
+		a.add(AuditUnmatched, section, meta, "not matched to an agent")
+	}
+	a.checkMedia(section, meta)
+
+	key := fmt.Sprintf("%s|%s|%d", meta.Type, strings.ToLower(strings.TrimSpace(meta.Title)), ui.PtrToInt(meta.Year))
+	a.titles[key] = append(a.titles[key], AuditFinding{
+		RatingKey: ui.PtrToString(meta.RatingKey),
+		Title:     auditTitle(meta),
+		Library:   section.Title,
+	})
+}
+
+// checkMedia reports items with several versions and files below the minimum resolution
+func (a *auditor) checkMedia(section AuditSection, meta components.Metadata) {
+	if len(meta.Media) > 1 {
+		a.add(AuditDuplicate, section, meta, fmt.Sprintf("%d versions", len(meta.Media)))
+	}
+	if a.opts.MinResolution <= 0 {
+		return
+	}
+	var low []string
+	for _, media := range meta.Media {
+		res := ui.PtrToString(media.VideoResolution)
+		height, ok := ParseResolution(res)
+		if !ok && media.Height != nil {
+			height, ok = *media.Height, true
+		}
+		if ok && height < a.opts.MinResolution {
+			if res == "" {
+				res = fmt.Sprintf("%dp", height)
+			}
+			low = append(low, res)
+		}
+	}
+	if len(low) > 0 {
+		a.add(AuditLowResolution, section, meta, fmt.Sprintf("%s below %dp", strings.Join(low, ", "), a.opts.MinResolution))
+	}
+}
+
+// checkEpisodes checks episode files and reports gaps in each season's episode numbers
+func (a *auditor) checkEpisodes(section AuditSection, episodes []components.Metadata) {
+	type season struct {
+		meta    components.Metadata
+		indexes []int
+	}
+	seasons := make(map[string]*season)
+	var order []string
+
+	for _, ep := range episodes {
+		a.checkMedia(section, ep)
+
+		// Specials are rarely complete, so they are not checked for gaps
+		if ep.Index == nil || ep.ParentIndex == nil || *ep.ParentIndex == 0 {
+			continue
+		}
+		key := ui.PtrToString(ep.ParentRatingKey)
+		if key == "" {
+			key = fmt.Sprintf("%s/%d", ui.PtrToString(ep.GrandparentRatingKey), *ep.ParentIndex)
+		}
+		s, ok := seasons[key]
+		if !ok {
+			s = &season{meta: ep}
+			seasons[key] = s
+			order = append(order, key)
+		}
+		s.indexes = append(s.indexes, *ep.Index)
+	}
+
+	for _, key := range order {
+		s := seasons[key]
+		var missing []string
+		for i := 1; i <= slices.Max(s.indexes); i++ {
+			if !slices.Contains(s.indexes, i) {
+				missing = append(missing, fmt.Sprintf("E%02d", i))
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		seasonMeta := components.Metadata{
+			RatingKey: s.meta.ParentRatingKey,
+			Title:     fmt.Sprintf("%s / Season %d", ui.PtrToString(s.meta.GrandparentTitle), *s.meta.ParentIndex),
+			Type:      "season",
+		}
+		a.add(AuditMissingEpisodes, section, seasonMeta, "missing "+strings.Join(missing, ", "))
+	}
+}
+
+// checkTitles reports movies and shows with the same title and year, e.g. the same movie in two libraries
+func (a *auditor) checkTitles() {
+	if !a.opts.enabled(AuditDuplicate) {
+		return
+	}
+	keys := make([]string, 0, len(a.titles))
+	for k, items := range a.titles {
+		if len(items) > 1 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		items := a.titles[k]
+		for i, item := range items {
+			var others []string
+			for j, other := range items {
+				if i != j {
+					others = append(others, fmt.Sprintf("%s (%s)", other.Library, other.RatingKey))
+				}
+			}
+			item.Check = AuditDuplicate
+			item.Detail = "also in " + strings.Join(others, ", ")
+			a.findings = append(a.findings, item)
+		}
+	}
+}
+
+// ParseResolution converts a resolution such as 720, 1080p, 4k or sd to its vertical size
+func ParseResolution(s string) (int, bool) {
+	switch s = strings.ToLower(strings.TrimSuffix(strings.ToLower(s), "p")); s {
+	case "":
+		return 0, false
+	case "sd":
+		return 480, true
+	case "4k":
+		return 2160, true
+	case "8k":
+		return 4320, true
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+func auditTitle(meta components.Metadata) string {
+	title := meta.Title
+	if meta.Type == "episode" {
+		title = fmt.Sprintf("%s / S%02dE%02d / %s", ui.PtrToString(meta.GrandparentTitle), ui.PtrToInt(meta.ParentIndex), ui.PtrToInt(meta.Index), meta.Title)
+	}
+	if meta.Year != nil && meta.Type != "episode" {
+		title = fmt.Sprintf("%s (%d)", title, *meta.Year)
+	}
+	return title
+}
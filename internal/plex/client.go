@@ -2,15 +2,18 @@ package plex
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"regexp"
 	"runtime"
 	"time"
 
 	"github.com/LukeHagar/plexgo"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/config"
 )
 
@@ -24,12 +27,12 @@ type loggingTransport struct {
 }
 
 func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	t.cfg.Logger.Debug("SDK Request", "method", req.Method, "url", req.URL.String())
+	t.cfg.Logger.Debug("SDK Request", "method", req.Method, "url", redact(req.URL.String()))
 
 	if t.cfg.Enabled(config.LevelTrace) {
 		dump, err := httputil.DumpRequestOut(req, t.cfg.Verbosity >= 3)
 		if err == nil {
-			t.cfg.Logger.Log(context.Background(), config.LevelTrace, "SDK Request Dump", "dump", string(dump))
+			t.cfg.Logger.Log(context.Background(), config.LevelTrace, "SDK Request Dump", "dump", redact(string(dump)))
 		}
 	}
 
@@ -38,7 +41,7 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	duration := time.Since(start)
 
 	if err != nil {
-		t.cfg.Logger.Error("SDK Request Failed", "error", err, "duration", duration)
+		t.cfg.Logger.Error("SDK Request Failed", "error", redact(err.Error()), "duration", duration)
 		return nil, err
 	}
 
@@ -47,35 +50,97 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	if t.cfg.Enabled(config.LevelTrace) {
 		dump, err := httputil.DumpResponse(res, t.verbosityBody())
 		if err == nil {
-			t.cfg.Logger.Log(context.Background(), config.LevelTrace, "SDK Response Dump", "dump", string(dump))
+			t.cfg.Logger.Log(context.Background(), config.LevelTrace, "SDK Response Dump", "dump", redact(string(dump)))
 		}
 	}
 
 	return res, nil
 }
 
+// tokenPattern matches tokens in headers, query strings and JSON bodies
+var tokenPattern = regexp.MustCompile(`(?i)((?:x-plex-token|authToken|accessToken)"?\s*[:=]\s*"?)[^&\s",]+`)
+
+// redact hides tokens in what is logged
+func redact(s string) string {
+	return tokenPattern.ReplaceAllString(s, "${1}REDACTED")
+}
+
 func (t *loggingTransport) verbosityBody() bool {
 	return t.cfg.Verbosity >= 3
 }
 
 func NewClient() (*Client, error) {
+	token, source := serverToken()
+	slog.Debug("NewClient: Initializing with token", "source", source)
+	return NewClientWithToken(token)
+}
+
+// serverToken resolves the token used for server requests and where it came from
+func serverToken() (string, string) {
 	cfg := config.Get()
 
 	// 1. Check for Token (Global)
-	token := os.Getenv("PLEXCTL_TOKEN")
-	source := "env"
-	if token == "" {
-		token = cfg.Token
-		source = "main account"
-		// If a home user access token is set, it overrides the main account token
-		if cfg.HomeUser.AccessToken != "" {
-			token = cfg.HomeUser.AccessToken
-			source = "home user access"
-		}
+	if token := os.Getenv("PLEXCTL_TOKEN"); token != "" {
+		return token, "env"
 	}
+	// If a home user access token is set, it overrides the main account token
+	if cfg.HomeUser.AccessToken != "" {
+		return cfg.HomeUser.AccessToken, "home user access"
+	}
+	return cfg.Token, "main account"
+}
 
-	slog.Debug("NewClient: Initializing with token", "source", source)
-	return NewClientWithToken(token)
+// serverGet sends an authenticated GET request for a path of the active server through client. The token
+// of the active home user or account goes in a header, never in the URL. Statuses other than 200 OK are
+// returned as errors, otherwise the caller closes the body.
+func serverGet(ctx context.Context, client *http.Client, path, accept string) (*http.Response, error) {
+	_, serverCfg, ok := config.Get().GetActiveServer()
+	if !ok {
+		return nil, fmt.Errorf("no active server")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", serverCfg.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	token, _ := serverToken()
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Client-Identifier", config.ClientIdentifier())
+	req.Header.Set("Accept", accept)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, clierr.FromStatus(resp.StatusCode, "request to %s failed: %s", req.URL.Path, resp.Status)
+	}
+	return resp, nil
+}
+
+// serverGetJSON fetches a server path the SDK has no operation for and decodes the JSON response
+func serverGetJSON(ctx context.Context, path string, out any) error {
+	resp, err := serverGet(ctx, sharedHTTPClient(), path, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// StreamHeaders are the headers authenticating requests for media and subtitles, for players that fetch
+// them from the server themselves
+func StreamHeaders() []string {
+	token, _ := serverToken()
+	return []string{
+		"X-Plex-Token: " + token,
+		"X-Plex-Client-Identifier: " + config.ClientIdentifier(),
+	}
 }
 
 // NewHomeUserClient specifically uses the V2 AuthToken (general user token)
@@ -94,19 +159,12 @@ func NewClientWithToken(token string) (*Client, error) {
 	_, serverCfg, hasServer := cfg.GetActiveServer()
 
 	if token == "" {
-		return nil, fmt.Errorf("plex token not found. please login with 'plexctl login' or set PLEXCTL_TOKEN")
-	}
-	httpClient := &http.Client{
-		Transport: &loggingTransport{
-			base: http.DefaultTransport,
-			cfg:  cfg,
-		},
-		Timeout: 60 * time.Second,
+		return nil, clierr.New(clierr.Auth, "plex token not found. please login with 'plexctl login' or set PLEXCTL_TOKEN")
 	}
 
 	opts := []plexgo.SDKOption{
 		plexgo.WithSecurity(token),
-		plexgo.WithClient(httpClient),
+		plexgo.WithClient(sharedHTTPClient()),
 		plexgo.WithClientIdentifier(config.ClientIdentifier()),
 		plexgo.WithProduct("plexctl"),
 		plexgo.WithDevice(runtime.GOOS),
@@ -0,0 +1,97 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// GetContinueWatching lists the items to resume, followed by the On Deck items (the next episodes of shows in
+// progress) that are not already listed. Each item is only returned once.
+func GetContinueWatching(ctx context.Context) ([]components.Metadata, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("SDK: Fetching continue watching")
+	res, err := client.SDK.Hubs.GetContinueWatching(ctx, operations.GetContinueWatchingRequest{
+		Count: ptr(int64(50)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get continue watching: %w", err)
+	}
+
+	var items []components.Metadata
+	if res.Object != nil && res.Object.MediaContainer != nil {
+		for _, hub := range res.Object.MediaContainer.Hub {
+			items = append(items, hub.Metadata...)
+		}
+	}
+
+	slog.Debug("SDK: Fetching on deck")
+	var onDeck components.MediaContainerWithMetadata
+	if err := serverGetJSON(ctx, "/library/onDeck", &onDeck); err != nil {
+		return nil, fmt.Errorf("failed to get on deck: %w", err)
+	}
+	if onDeck.MediaContainer != nil {
+		items = append(items, onDeck.MediaContainer.Metadata...)
+	}
+
+	seen := make(map[string]bool)
+	unique := items[:0]
+	for _, item := range items {
+		key := ui.PtrToString(item.RatingKey)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, item)
+	}
+	return unique, nil
+}
+
+// NextEpisode finds the episode of a show to watch next: the one in progress, otherwise the first
+// unwatched episode after the last watched one. Specials are skipped. Returns nil when the show is fully watched.
+func NextEpisode(ctx context.Context, client *Client, showKey string) (*components.Metadata, error) {
+	slog.Debug("SDK: Fetching episodes", "show", showKey)
+	res, err := client.SDK.Library.GetAllItemLeaves(ctx, operations.GetAllItemLeavesRequest{Ids: showKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list episodes: %w", err)
+	}
+	if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil {
+		return nil, nil
+	}
+
+	var episodes []components.Metadata
+	for _, ep := range res.MediaContainerWithMetadata.MediaContainer.Metadata {
+		if ui.PtrToInt(ep.ParentIndex) > 0 {
+			episodes = append(episodes, ep)
+		}
+	}
+	slices.SortStableFunc(episodes, func(a, b components.Metadata) int {
+		if d := ui.PtrToInt(a.ParentIndex) - ui.PtrToInt(b.ParentIndex); d != 0 {
+			return d
+		}
+		return ui.PtrToInt(a.Index) - ui.PtrToInt(b.Index)
+	})
+
+	lastWatched := -1
+	for i, ep := range episodes {
+		if ui.PtrToInt(ep.ViewOffset) > 0 {
+			return &episodes[i], nil
+		}
+		if ui.PtrToInt(ep.ViewCount) > 0 {
+			lastWatched = i
+		}
+	}
+	if lastWatched+1 < len(episodes) {
+		return &episodes[lastWatched+1], nil
+	}
+	return nil, nil
+}
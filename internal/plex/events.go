@@ -0,0 +1,325 @@
+package plex
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/cache"
+	"github.com/ygelfand/plexctl/internal/config"
+)
+
+// Server notification types the TUI subscribes to
+const (
+	EventPlaying  = "playing"
+	EventTimeline = "timeline"
+	EventActivity = "activity"
+)
+
+// Timeline states reported for library items
+const (
+	timelineStateCreated  = 0
+	timelineStateFinished = 5
+	timelineStateDeleted  = 9
+)
+
+const (
+	libraryIdentifier = "com.plexapp.plugins.library"
+	maxEventBackoff   = time.Minute
+	maxLibraryPages   = 1000
+)
+
+// ServerEvent is a single server notification that can affect cached data
+type ServerEvent struct {
+	Type      string
+	RatingKey string
+	SectionID string
+	State     string // playback state, timeline state or activity event
+	Section   bool   // the whole section changed, e.g. items were added or removed
+}
+
+// Changes describes what an event invalidated so views can refresh the affected data
+type Changes struct {
+	Sections []string // sections whose contents changed
+	Items    []string // items whose state changed, including their parents
+	Sessions bool     // the list of playback sessions changed
+}
+
+// Merge adds the changes in o to c
+func (c *Changes) Merge(o Changes) {
+	for _, s := range o.Sections {
+		if !slices.Contains(c.Sections, s) {
+			c.Sections = append(c.Sections, s)
+		}
+	}
+	for _, i := range o.Items {
+		if !slices.Contains(c.Items, i) {
+			c.Items = append(c.Items, i)
+		}
+	}
+	c.Sessions = c.Sessions || o.Sessions
+}
+
+// Empty reports whether nothing changed
+func (c Changes) Empty() bool {
+	return len(c.Sections) == 0 && len(c.Items) == 0 && !c.Sessions
+}
+
+// flexString accepts both JSON strings and numbers, the server is not consistent between them
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*f = flexString(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*f = flexString(n.String())
+	return nil
+}
+
+// flexList accepts a single object or a list of objects
+type flexList[T any] []T
+
+func (l *flexList[T]) UnmarshalJSON(data []byte) error {
+	if len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] == '[' {
+		return json.Unmarshal(data, (*[]T)(l))
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = flexList[T]{v}
+	return nil
+}
+
+type playingNotification struct {
+	SessionKey flexString `json:"sessionKey"`
+	RatingKey  flexString `json:"ratingKey"`
+	State      string     `json:"state"`
+}
+
+type timelineEntry struct {
+	Identifier string     `json:"identifier"`
+	SectionID  flexString `json:"sectionID"`
+	ItemID     flexString `json:"itemID"`
+	State      int        `json:"state"`
+}
+
+type activityNotification struct {
+	Event    string `json:"event"`
+	Activity struct {
+		Type    string `json:"type"`
+		Context struct {
+			LibrarySectionID flexString `json:"librarySectionID"`
+		} `json:"Context"`
+	} `json:"Activity"`
+}
+
+// notificationPayload covers both the EventSource format and the NotificationContainer wrapper
+type notificationPayload struct {
+	NotificationContainer *notificationPayload           `json:"NotificationContainer"`
+	Playing               flexList[playingNotification]  `json:"PlaySessionStateNotification"`
+	Timeline              flexList[timelineEntry]        `json:"TimelineEntry"`
+	Activity              flexList[activityNotification] `json:"ActivityNotification"`
+}
+
+// WatchEvents streams server notifications into events until ctx is cancelled, reconnecting with backoff
+func WatchEvents(ctx context.Context, events chan<- ServerEvent) {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := streamEvents(ctx, events)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > maxEventBackoff {
+			backoff = time.Second
+		}
+		slog.Debug("Events: Stream closed, reconnecting", "error", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxEventBackoff)
+	}
+}
+
+func streamEvents(ctx context.Context, events chan<- ServerEvent) error {
+	path := fmt.Sprintf("/:/eventsource/notifications?filters=%s,%s,%s", EventPlaying, EventTimeline, EventActivity)
+	// The stream stays open indefinitely, so it is read without a timeout
+	resp, err := serverGet(ctx, streamHTTPClient(), path, "text/event-stream")
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to notifications: %w", err)
+	}
+	defer resp.Body.Close()
+	slog.Debug("Events: Subscribed to server notifications")
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var eventType string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				for _, e := range parseNotification(eventType, []byte(data.String())) {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+			eventType = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("notification stream ended")
+}
+
+func parseNotification(eventType string, data []byte) []ServerEvent {
+	var payload notificationPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		slog.Log(context.Background(), config.LevelTrace, "Events: Ignoring unparseable notification", "type", eventType, "error", err)
+		return nil
+	}
+	if payload.NotificationContainer != nil {
+		payload = *payload.NotificationContainer
+	}
+
+	var res []ServerEvent
+	for _, p := range payload.Playing {
+		res = append(res, ServerEvent{Type: EventPlaying, RatingKey: string(p.RatingKey), State: p.State})
+	}
+	for _, t := range payload.Timeline {
+		if t.Identifier != libraryIdentifier || (t.State != timelineStateFinished && t.State != timelineStateDeleted && t.State != timelineStateCreated) {
+			continue
+		}
+		res = append(res, ServerEvent{
+			Type:      EventTimeline,
+			RatingKey: string(t.ItemID),
+			SectionID: string(t.SectionID),
+			State:     strconv.Itoa(t.State),
+			Section:   t.State != timelineStateFinished,
+		})
+	}
+	for _, a := range payload.Activity {
+		sectionID := string(a.Activity.Context.LibrarySectionID)
+		if a.Event != "ended" || sectionID == "" {
+			continue
+		}
+		res = append(res, ServerEvent{Type: EventActivity, SectionID: sectionID, State: a.Event, Section: true})
+	}
+	return res
+}
+
+// Invalidate drops the cache entries affected by e and reports what changed
+func Invalidate(e ServerEvent) Changes {
+	var changes Changes
+	if e.Type == EventPlaying {
+		changes.Sessions = true
+		// Progress is only saved when playback pauses or stops
+		if e.State != "paused" && e.State != "stopped" {
+			return changes
+		}
+	}
+
+	if e.RatingKey != "" && !e.Section {
+		keys, sectionID := InvalidateItem(e.RatingKey)
+		changes.Items = keys
+		if e.SectionID == "" {
+			e.SectionID = sectionID
+		}
+	}
+	if e.SectionID != "" {
+		InvalidateSection(e.SectionID)
+		if e.Section {
+			changes.Sections = []string{e.SectionID}
+		}
+	}
+
+	slog.Debug("Events: Invalidated cache", "type", e.Type, "state", e.State, "items", changes.Items, "sections", changes.Sections)
+	return changes
+}
+
+// InvalidateItem drops the cached metadata and children of an item and its parents. It returns
+// the rating keys that were affected and the item's library section if known.
+func InvalidateItem(ratingKey string) ([]string, string) {
+	cfg := config.Get()
+	serverID, _, _ := cfg.GetActiveServer()
+	cm, err := cache.Get(cfg.CacheDir)
+	if err != nil {
+		return []string{ratingKey}, ""
+	}
+
+	keys := []string{ratingKey}
+	sectionID := ""
+	var body components.MediaContainerWithMetadata
+	if err := cm.Get(cm.GenerateKey(serverID, metadataRequest(ratingKey)), &body); err == nil &&
+		body.MediaContainer != nil && len(body.MediaContainer.Metadata) > 0 {
+		meta := body.MediaContainer.Metadata[0]
+		for _, k := range []*string{meta.ParentRatingKey, meta.GrandparentRatingKey} {
+			if k != nil && *k != "" {
+				keys = append(keys, *k)
+			}
+		}
+		switch sid := meta.AdditionalProperties["librarySectionID"].(type) {
+		case string:
+			sectionID = sid
+		case float64:
+			sectionID = fmt.Sprintf("%.0f", sid)
+		}
+	}
+
+	for _, k := range keys {
+		_ = cm.Delete(cm.GenerateKey(serverID, metadataRequest(k)))
+		_ = cm.Delete(childrenCacheKey(serverID, k))
+	}
+	return keys, sectionID
+}
+
+// InvalidateSection drops the cached library pages and item count of a section
+func InvalidateSection(sectionID string) {
+	cfg := config.Get()
+	if cfg.NoCache {
+		return
+	}
+	serverID, _, _ := cfg.GetActiveServer()
+	cm, err := cache.Get(cfg.CacheDir)
+	if err != nil {
+		return
+	}
+
+	_ = cm.Delete(cm.GenerateKey(serverID, operations.ListContentRequest{SectionID: sectionID}))
+	// Pages are always loaded in order, so the first missing page ends the cached range
+	for page := 0; page < maxLibraryPages; page++ {
+		if err := cm.Delete(cm.GenerateKey(serverID, LibraryPageRequest(sectionID, page*LibraryPageSize))); err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/ygelfand/plexctl/internal/timerange"
+)
+
+// Resolutions accepted by the server's resolution filter
+var Resolutions = []string{"4k", "1080", "720", "480", "sd"}
+
+// MetadataTypes maps item types to the server type numbers used to list them, along with the kind of library holding them
+var MetadataTypes = map[string]struct {
+	Number  string
+	Section components.MediaTypeString
+}{
+	"movie":   {"1", components.MediaTypeStringMovie},
+	"show":    {"2", components.MediaTypeStringTvShow},
+	"season":  {"3", components.MediaTypeStringTvShow},
+	"episode": {"4", components.MediaTypeStringTvShow},
+	"artist":  {"8", components.MediaTypeStringArtist},
+	"album":   {"9", components.MediaTypeStringArtist},
+	"track":   {"10", components.MediaTypeStringArtist},
+	"photo":   {"13", components.MediaTypeStringPhoto},
+}
+
+// LibraryFilter narrows and orders a library listing on the server
+type LibraryFilter struct {
+	Genres     []string
+	Actors     []string
+	Labels     []string
+	Year       string // a single year or a range like 2010-2019
+	Unwatched  bool
+	Resolution string
+	Added      timerange.Range
+	Sort       string // server sort such as addedAt:desc
+}
+
+// Query builds the server query for the filter, resolving tag names to their IDs in the section
+func (f LibraryFilter) Query(ctx context.Context, sectionID string) (url.Values, error) {
+	query := url.Values{}
+
+	tags := []struct {
+		field  string
+		values []string
+	}{
+		{"genre", f.Genres},
+		{"actor", f.Actors},
+		{"label", f.Labels},
+	}
+	for _, t := range tags {
+		if len(t.values) == 0 {
+			continue
+		}
+		ids, err := resolveTags(ctx, sectionID, t.field, t.values)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(t.field, strings.Join(ids, ","))
+	}
+
+	if f.Year != "" {
+		from, to, isRange := strings.Cut(f.Year, "-")
+		if !isRange {
+			to = from
+		}
+		for _, y := range []string{from, to} {
+			if _, err := strconv.Atoi(y); err != nil {
+				return nil, fmt.Errorf("invalid year '%s', expected a year or a range like 2010-2019", f.Year)
+			}
+		}
+		if isRange {
+			query.Set("year>>", from)
+			query.Set("year<<", to)
+		} else {
+			query.Set("year", from)
+		}
+	}
+
+	if f.Unwatched {
+		query.Set("unwatched", "1")
+	}
+
+	if f.Resolution != "" {
+		res := strings.ToLower(f.Resolution)
+		if !slices.Contains(Resolutions, res) {
+			return nil, fmt.Errorf("invalid resolution '%s' (allowed: %s)", f.Resolution, strings.Join(Resolutions, ", "))
+		}
+		query.Set("resolution", res)
+	}
+
+	if !f.Added.From.IsZero() {
+		query.Set("addedAt>>", strconv.FormatInt(f.Added.From.Unix(), 10))
+	}
+	if !f.Added.To.IsZero() {
+		query.Set("addedAt<<", strconv.FormatInt(f.Added.To.Unix(), 10))
+	}
+
+	if f.Sort != "" {
+		query.Set("sort", f.Sort)
+	}
+
+	return query, nil
+}
+
+// resolveTags maps tag names such as genres to the IDs the server filters on. Numeric values are used as is.
+func resolveTags(ctx context.Context, sectionID, field string, values []string) ([]string, error) {
+	var body struct {
+		MediaContainer struct {
+			Directory []struct {
+				Key   flexString `json:"key"`
+				Title string     `json:"title"`
+			} `json:"Directory"`
+		} `json:"MediaContainer"`
+	}
+	loaded := false
+
+	var ids []string
+	for _, v := range values {
+		if _, err := strconv.Atoi(v); err == nil {
+			ids = append(ids, v)
+			continue
+		}
+		if !loaded {
+			slog.Debug("SDK: Fetching filter values", "section", sectionID, "field", field)
+			if err := serverGetJSON(ctx, fmt.Sprintf("/library/sections/%s/%s", url.PathEscape(sectionID), field), &body); err != nil {
+				return nil, fmt.Errorf("failed to list %s values: %w", field, err)
+			}
+			loaded = true
+		}
+
+		found := false
+		for _, d := range body.MediaContainer.Directory {
+			if strings.EqualFold(d.Title, v) {
+				ids = append(ids, string(d.Key))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no %s named '%s' in library %s", field, v, sectionID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,67 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// historyPageSize is the number of history entries requested per page
+const historyPageSize = 500
+
+// metadataBatchSize is the number of items requested at once by GetMetadataItems
+const metadataBatchSize = 50
+
+// ListAllPlaybackHistory pages through every history entry matching the request
+func ListAllPlaybackHistory(ctx context.Context, client *Client, req operations.ListPlaybackHistoryRequest) ([]operations.ListPlaybackHistoryMetadata, error) {
+	var all []operations.ListPlaybackHistoryMetadata
+	for start := 0; ; {
+		// The request has no paging fields, the server also reads them from the headers
+		paging := operations.WithSetHeaders(map[string]string{
+			"X-Plex-Container-Start": strconv.Itoa(start),
+			"X-Plex-Container-Size":  strconv.Itoa(historyPageSize),
+		})
+		slog.Debug("SDK: Fetching history page", "start", start)
+		res, err := client.SDK.Status.ListPlaybackHistory(ctx, req, paging)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history: %w", err)
+		}
+		if res.Object == nil || res.Object.MediaContainer == nil {
+			break
+		}
+
+		mc := res.Object.MediaContainer
+		all = append(all, mc.Metadata...)
+		start += len(mc.Metadata)
+		if len(mc.Metadata) < historyPageSize || (mc.TotalSize != nil && int64(start) >= *mc.TotalSize) {
+			break
+		}
+	}
+	return all, nil
+}
+
+// GetMetadataItems fetches the metadata of many items by rating key, several per request.
+// Items that are no longer on the server are left out of the result.
+func GetMetadataItems(ctx context.Context, client *Client, ratingKeys []string) (map[string]components.Metadata, error) {
+	items := make(map[string]components.Metadata, len(ratingKeys))
+	for i := 0; i < len(ratingKeys); i += metadataBatchSize {
+		batch := ratingKeys[i:min(i+metadataBatchSize, len(ratingKeys))]
+		slog.Debug("SDK: Fetching metadata batch", "count", len(batch))
+		res, err := client.SDK.Content.GetMetadataItem(ctx, operations.GetMetadataItemRequest{Ids: batch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata: %w", err)
+		}
+		if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil {
+			continue
+		}
+		for _, meta := range res.MediaContainerWithMetadata.MediaContainer.Metadata {
+			items[ui.PtrToString(meta.RatingKey)] = meta
+		}
+	}
+	return items, nil
+}
@@ -2,17 +2,16 @@ package plex
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
-	"net/http"
-	"strings"
+	"net/url"
 	"time"
 
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/ygelfand/plexctl/internal/cache"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/config"
 )
 
@@ -72,10 +71,7 @@ func GetMetadata(ctx context.Context, ratingKey string, force bool) (*components
 	}
 
 	var body components.MediaContainerWithMetadata
-	req := operations.GetMetadataItemRequest{
-		Ids:           []string{ratingKey},
-		IncludeExtras: components.BoolIntTrue.ToPointer(),
-	}
+	req := metadataRequest(ratingKey)
 
 	ttl := MediaCacheTTL
 	if force {
@@ -88,7 +84,7 @@ func GetMetadata(ctx context.Context, ratingKey string, force bool) (*components
 			return nil, err
 		}
 		if res.MediaContainerWithMetadata == nil {
-			return nil, fmt.Errorf("metadata not found")
+			return nil, clierr.New(clierr.NotFound, "metadata not found")
 		}
 		return res.MediaContainerWithMetadata, nil
 	})
@@ -97,12 +93,24 @@ func GetMetadata(ctx context.Context, ratingKey string, force bool) (*components
 	}
 
 	if len(body.MediaContainer.Metadata) == 0 {
-		return nil, fmt.Errorf("metadata empty")
+		return nil, clierr.New(clierr.NotFound, "metadata empty")
 	}
 
 	return &body.MediaContainer.Metadata[0], nil
 }
 
+// metadataRequest builds the request used for every metadata lookup so its cache entry can be found again
+func metadataRequest(ratingKey string) operations.GetMetadataItemRequest {
+	return operations.GetMetadataItemRequest{
+		Ids:           []string{ratingKey},
+		IncludeExtras: components.BoolIntTrue.ToPointer(),
+	}
+}
+
+func childrenCacheKey(serverID, ratingKey string) string {
+	return fmt.Sprintf("%s/children/%s", serverID, ratingKey)
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -110,7 +118,7 @@ func ptr[T any](v T) *T {
 // GetChildren retrieves children of an item (e.g. seasons of a show, or episodes of a season)
 func GetChildren(ctx context.Context, ratingKey string) ([]components.Metadata, error) {
 	cfg := config.Get()
-	serverID, serverCfg, ok := cfg.GetActiveServer()
+	serverID, _, ok := cfg.GetActiveServer()
 	if !ok {
 		return nil, fmt.Errorf("no active server")
 	}
@@ -120,38 +128,14 @@ func GetChildren(ctx context.Context, ratingKey string) ([]components.Metadata,
 		return nil, err
 	}
 
-	cacheKey := fmt.Sprintf("%s/children/%s", serverID, ratingKey)
+	cacheKey := childrenCacheKey(serverID, ratingKey)
 	var body components.MediaContainerWithMetadata
 	if err := cm.Get(cacheKey, &body); err == nil {
 		return body.MediaContainer.Metadata, nil
 	}
 
-	url := fmt.Sprintf("%s/library/metadata/%s/children", serverCfg.URL, ratingKey)
-	separator := "?"
-	if strings.Contains(url, "?") {
-		separator = "&"
-	}
-	url = fmt.Sprintf("%s%sX-Plex-Token=%s", url, separator, cfg.Token)
-	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch children: %s", resp.Status)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal(data, &body); err != nil {
-		return nil, fmt.Errorf("failed to parse children: %w", err)
+	if err := serverGetJSON(ctx, fmt.Sprintf("/library/metadata/%s/children", ratingKey), &body); err != nil {
+		return nil, fmt.Errorf("failed to fetch children: %w", err)
 	}
 
 	if body.MediaContainer == nil {
@@ -165,7 +149,7 @@ func GetChildren(ctx context.Context, ratingKey string) ([]components.Metadata,
 // GetImage retrieves image data (thumb/poster) and caches it
 func GetImage(ctx context.Context, path string) ([]byte, error) {
 	cfg := config.Get()
-	serverID, serverCfg, ok := cfg.GetActiveServer()
+	serverID, _, ok := cfg.GetActiveServer()
 	if !ok {
 		return nil, fmt.Errorf("no active server")
 	}
@@ -181,22 +165,12 @@ func GetImage(ctx context.Context, path string) ([]byte, error) {
 		return data, nil
 	}
 
-	url := fmt.Sprintf("%s%s", serverCfg.URL, path)
-	separator := "?"
-	if strings.Contains(url, "?") {
-		separator = "&"
-	}
-	url = fmt.Sprintf("%s%sX-Plex-Token=%s", url, separator, cfg.Token)
-	resp, err := http.Get(url)
+	resp, err := serverGet(ctx, sharedHTTPClient(), path, "image/*")
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch image: %s", resp.Status)
-	}
-
 	data, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -227,3 +201,25 @@ func GetHomeHubs(ctx context.Context) ([]components.Hub, error) {
 
 	return res.Object.MediaContainer.Hub, nil
 }
+
+// WebLink returns the Plex Web address of an item on the active server
+func WebLink(ratingKey string) string {
+	serverID, _, ok := config.Get().GetActiveServer()
+	if !ok || ratingKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://app.plex.tv/desktop/#!/server/%s/details?key=%s", serverID, url.QueryEscape("/library/metadata/"+ratingKey))
+}
+
+// FindByGUID finds the library items matching a GUID such as plex://movie/5d776825880197001ec967c6
+func FindByGUID(ctx context.Context, guid string) ([]components.Metadata, error) {
+	slog.Debug("SDK: Finding items by GUID", "guid", guid)
+	var body components.MediaContainerWithMetadata
+	if err := serverGetJSON(ctx, "/library/all?guid="+url.QueryEscape(guid), &body); err != nil {
+		return nil, fmt.Errorf("failed to find %s: %w", guid, err)
+	}
+	if body.MediaContainer == nil {
+		return nil, nil
+	}
+	return body.MediaContainer.Metadata, nil
+}
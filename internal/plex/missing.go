@@ -0,0 +1,118 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// SeasonGaps describes the episodes missing from one season of a show
+type SeasonGaps struct {
+	RatingKey  string `json:"rating_key,omitempty"`
+	Season     int    `json:"season"`
+	Special    bool   `json:"special"`
+	Present    int    `json:"present"`
+	Missing    []int  `json:"missing"`
+	Unnumbered int    `json:"unnumbered,omitempty"` // episodes without an episode number
+	Absent     bool   `json:"absent,omitempty"`     // the whole season is missing from the library
+}
+
+// MissingCount returns how many episodes are missing, an absent season counts as one
+func (g SeasonGaps) MissingCount() int {
+	if g.Absent {
+		return 1
+	}
+	return len(g.Missing)
+}
+
+// EpisodeCodes returns the missing episodes as S01E02 codes
+func (g SeasonGaps) EpisodeCodes() []string {
+	codes := make([]string, 0, len(g.Missing))
+	for _, e := range g.Missing {
+		codes = append(codes, fmt.Sprintf("S%02dE%02d", g.Season, e))
+	}
+	return codes
+}
+
+// FindSeasonGaps compares the episodes of a season against the numbers implied by their sequence.
+// Every number up to the highest present episode is expected, later episodes cannot be known.
+func FindSeasonGaps(season components.Metadata, episodes []components.Metadata) SeasonGaps {
+	g := SeasonGaps{
+		RatingKey: ui.PtrToString(season.RatingKey),
+		Season:    ui.PtrToInt(season.Index),
+		Special:   ui.PtrToInt(season.Index) == 0,
+		Present:   len(episodes),
+	}
+
+	var indexes []int
+	for _, ep := range episodes {
+		if ep.Index == nil {
+			g.Unnumbered++
+			continue
+		}
+		indexes = append(indexes, *ep.Index)
+	}
+	if len(indexes) == 0 {
+		return g
+	}
+
+	for i := 1; i <= slices.Max(indexes); i++ {
+		if !slices.Contains(indexes, i) {
+			g.Missing = append(g.Missing, i)
+		}
+	}
+	return g
+}
+
+// FindShowGaps reports the seasons of a show that have missing episodes, including seasons
+// absent from the library between the first and last present one. A season whose leafCount
+// says it holds no episodes counts as absent. Specials are only checked when includeSpecials
+// is set, as they are rarely numbered without gaps.
+func FindShowGaps(ctx context.Context, showKey string, includeSpecials bool) ([]SeasonGaps, error) {
+	seasons, err := GetChildren(ctx, showKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list seasons: %w", err)
+	}
+
+	var res []SeasonGaps
+	var numbers []int
+	for _, season := range seasons {
+		if season.Index == nil || season.RatingKey == nil {
+			continue
+		}
+		if season.LeafCount != nil && *season.LeafCount == 0 {
+			// Left behind when its episodes were deleted, reported below with the absent seasons
+			continue
+		}
+		numbers = append(numbers, *season.Index)
+		if *season.Index == 0 && !includeSpecials {
+			continue
+		}
+
+		episodes, err := GetChildren(ctx, *season.RatingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list episodes of season %d: %w", *season.Index, err)
+		}
+		if g := FindSeasonGaps(season, episodes); len(g.Missing) > 0 {
+			res = append(res, g)
+		}
+	}
+
+	if len(numbers) > 0 {
+		for i := 1; i < slices.Max(numbers); i++ {
+			if !slices.Contains(numbers, i) {
+				g := SeasonGaps{Season: i, Absent: true}
+				if empty := slices.IndexFunc(seasons, func(s components.Metadata) bool { return ui.PtrToInt(s.Index) == i }); empty >= 0 {
+					g.RatingKey = ui.PtrToString(seasons[empty].RatingKey)
+				}
+				res = append(res, g)
+			}
+		}
+	}
+
+	slices.SortFunc(res, func(a, b SeasonGaps) int { return a.Season - b.Season })
+	return res, nil
+}
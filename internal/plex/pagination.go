@@ -2,18 +2,46 @@ package plex
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/ygelfand/plexctl/internal/ui"
 )
 
+// LibraryPageSize is the number of items the TUI loads per library page
+const LibraryPageSize = 100
+
+// LibraryPageRequest builds the request for one TUI library page, shared with cache invalidation
+func LibraryPageRequest(sectionID string, start int) operations.ListContentRequest {
+	return operations.ListContentRequest{
+		SectionID:           sectionID,
+		XPlexContainerStart: ui.Ptr(start),
+		XPlexContainerSize:  ui.Ptr(LibraryPageSize),
+	}
+}
+
 // ContentWalker is a function that fetches a single page of results
 type ContentWalker func(ctx context.Context, start, size int) ([]components.Metadata, int64, error)
 
 // WalkContent handles either fetching a single page or walking all pages based on the provided options
 func WalkContent(ctx context.Context, all bool, page, count int, walker ContentWalker) ([]components.Metadata, error) {
 	var allMetadata []components.Metadata
+	err := WalkContentPages(ctx, all, page, count, walker, func(metadata []components.Metadata) error {
+		allMetadata = append(allMetadata, metadata...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allMetadata, nil
+}
+
+// WalkContentPages is WalkContent calling fn with each page as it arrives instead of collecting them.
+// An error from fn stops the walk and is returned.
+func WalkContentPages(ctx context.Context, all bool, page, count int, walker ContentWalker, fn func([]components.Metadata) error) error {
 	start := 0
 	size := count
 
@@ -26,10 +54,14 @@ func WalkContent(ctx context.Context, all bool, page, count int, walker ContentW
 	for {
 		metadata, totalSize, err := walker(ctx, start, size)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		allMetadata = append(allMetadata, metadata...)
+		if len(metadata) > 0 {
+			if err := fn(metadata); err != nil {
+				return err
+			}
+		}
 
 		if !all {
 			break
@@ -44,7 +76,7 @@ func WalkContent(ctx context.Context, all bool, page, count int, walker ContentW
 		}
 	}
 
-	return allMetadata, nil
+	return nil
 }
 
 // LibraryWalker returns a ContentWalker for a specific library section
@@ -74,3 +106,32 @@ func LibraryWalker(client *Client, sectionID string) ContentWalker {
 		return mc.Metadata, total, nil
 	}
 }
+
+// FilteredLibraryWalker returns a ContentWalker for a library section listing the items matching query,
+// such as a LibraryFilter query with filters the SDK request has no fields for
+func FilteredLibraryWalker(sectionID string, query url.Values) ContentWalker {
+	return func(ctx context.Context, start, size int) ([]components.Metadata, int64, error) {
+		page := url.Values{}
+		for k, v := range query {
+			page[k] = v
+		}
+		page.Set("X-Plex-Container-Start", strconv.Itoa(start))
+		page.Set("X-Plex-Container-Size", strconv.Itoa(size))
+
+		var body components.MediaContainerWithMetadata
+		path := fmt.Sprintf("/library/sections/%s/all?%s", url.PathEscape(sectionID), page.Encode())
+		if err := serverGetJSON(ctx, path, &body); err != nil {
+			return nil, 0, err
+		}
+		if body.MediaContainer == nil {
+			return nil, 0, nil
+		}
+
+		mc := body.MediaContainer
+		total := int64(0)
+		if mc.TotalSize != nil {
+			total = *mc.TotalSize
+		}
+		return mc.Metadata, total, nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/ygelfand/plexctl/internal/cache"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/config"
 )
 
@@ -155,10 +156,7 @@ func (s *Store) GetMetadata(ctx context.Context, ratingKey string, force bool) (
 	serverID, _, _ := cfg.GetActiveServer()
 
 	var body components.MediaContainerWithMetadata
-	req := operations.GetMetadataItemRequest{
-		Ids:           []string{ratingKey},
-		IncludeExtras: components.BoolIntTrue.ToPointer(),
-	}
+	req := metadataRequest(ratingKey)
 
 	ttl := MediaCacheTTL
 	if force {
@@ -171,7 +169,7 @@ func (s *Store) GetMetadata(ctx context.Context, ratingKey string, force bool) (
 			return nil, err
 		}
 		if res.MediaContainerWithMetadata == nil {
-			return nil, fmt.Errorf("metadata not found")
+			return nil, clierr.New(clierr.NotFound, "metadata not found")
 		}
 		return res.MediaContainerWithMetadata, nil
 	})
@@ -0,0 +1,279 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/config"
+)
+
+// maxRetryBackoff caps the delay between retries, including delays asked for with Retry-After
+const maxRetryBackoff = 30 * time.Second
+
+var (
+	// sharedHTTPClient sends every request to the server, so connections and limits are shared
+	sharedHTTPClient = sync.OnceValue(func() *http.Client { return newHTTPClient(config.Get().HTTP) })
+
+	// streamHTTPClient is for responses that stay open indefinitely, such as the notification stream,
+	// with no timeout and no retries. They do not take a slot under the concurrency limit, which they
+	// would hold for as long as they stay open.
+	streamHTTPClient = sync.OnceValue(func() *http.Client {
+		policy := config.Get().HTTP
+		policy.TimeoutSeconds, policy.Retries, policy.MaxConcurrent = 0, 0, 0
+		return newHTTPClient(policy)
+	})
+)
+
+// newHTTPClient returns a client sending requests through the retry, rate limiting and breaker policy,
+// logging each attempt
+func newHTTPClient(policy config.HTTPConfig) *http.Client {
+	return &http.Client{
+		Transport: &policyTransport{
+			base:   &loggingTransport{base: http.DefaultTransport, cfg: config.Get()},
+			policy: policy,
+		},
+	}
+}
+
+// policyTransport retries idempotent requests, limits the requests made to each server and fails
+// fast while a server keeps failing
+type policyTransport struct {
+	base   http.RoundTripper
+	policy config.HTTPConfig
+}
+
+func (t *policyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	guard := guardFor(req.URL.Host)
+	retries := t.policy.Retries
+	if !idempotent(req) {
+		retries = 0
+	}
+
+	if err := guard.allow(t.policy); err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		res, err := t.attempt(req, guard)
+		guard.record(t.policy, failed(res, err))
+
+		// A failure opening the breaker is returned as it is, later requests fail fast
+		retry, wait := t.shouldRetry(req, res, err, attempt)
+		if !retry || attempt >= retries || guard.allow(t.policy) != nil {
+			return res, err
+		}
+		if res != nil {
+			// The body is dropped so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+			res.Body.Close()
+		}
+		slog.Debug("HTTP: Retrying request", "url", req.URL.Redacted(), "attempt", attempt+1, "wait", wait)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attempt sends the request once, within the rate and concurrency limits and the timeout of an attempt
+func (t *policyTransport) attempt(req *http.Request, guard *serverGuard) (*http.Response, error) {
+	ctx := req.Context()
+	release, err := guard.acquire(ctx, t.policy)
+	if err != nil {
+		return nil, err
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if t.policy.TimeoutSeconds > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(t.policy.TimeoutSeconds)*time.Second)
+	}
+	attemptReq := req.Clone(ctx)
+	if req.Body != nil && req.GetBody != nil {
+		if attemptReq.Body, err = req.GetBody(); err != nil {
+			cancel()
+			release()
+			return nil, err
+		}
+	}
+
+	res, err := t.base.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		release()
+		return nil, err
+	}
+	// The timeout and the slot cover reading the body, so they end when the body is closed
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel, release: release}
+	return res, nil
+}
+
+// shouldRetry reports whether a failed attempt is worth repeating and how long to wait first
+func (t *policyTransport) shouldRetry(req *http.Request, res *http.Response, err error, attempt int) (bool, time.Duration) {
+	if req.Context().Err() != nil {
+		return false, 0
+	}
+	wait := backoff(time.Duration(t.policy.RetryBackoffMS)*time.Millisecond, attempt)
+
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout(), wait
+	}
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode < 500 {
+		return false, 0
+	}
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		wait = min(time.Duration(seconds)*time.Second, maxRetryBackoff)
+	}
+	return true, wait
+}
+
+// backoff doubles the base delay for each attempt, picking a random delay in its upper half
+func backoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	d := min(base<<attempt, maxRetryBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+// idempotent reports whether a request can be sent again without changing its effect
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+// failed reports whether an attempt counts against the breaker: the server could not be reached,
+// timed out or said it is unavailable
+func failed(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// cancelOnClose ends an attempt when its body is closed, stopping its timeout and freeing its slot
+// under the concurrency limit once however often it is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel  context.CancelFunc
+	release func()
+	once    sync.Once
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.cancel()
+		b.release()
+	})
+	return err
+}
+
+// serverGuard holds the limits and breaker state of one server, shared by every client in the process
+type serverGuard struct {
+	host  string
+	slots chan struct{}
+
+	mu        sync.Mutex
+	next      time.Time // earliest start of the next request under the rate limit
+	failures  int
+	openUntil time.Time
+}
+
+var (
+	guards   = make(map[string]*serverGuard)
+	guardsMu sync.Mutex
+)
+
+func guardFor(host string) *serverGuard {
+	guardsMu.Lock()
+	defer guardsMu.Unlock()
+	g, ok := guards[host]
+	if !ok {
+		g = &serverGuard{host: host}
+		guards[host] = g
+	}
+	return g
+}
+
+// allow fails fast while the breaker is open. Once the cooldown has passed requests go through again,
+// and the first failure opens it again.
+func (g *serverGuard) allow(policy config.HTTPConfig) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if policy.BreakerThreshold <= 0 || time.Now().After(g.openUntil) {
+		return nil
+	}
+	return clierr.New(clierr.Unreachable, "%s is not responding, giving up for %s after %d failed requests",
+		g.host, time.Until(g.openUntil).Round(time.Second), g.failures)
+}
+
+// record counts consecutive failures, opening the breaker when they reach the threshold
+func (g *serverGuard) record(policy config.HTTPConfig, failed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !failed {
+		g.failures = 0
+		return
+	}
+	g.failures++
+	if policy.BreakerThreshold > 0 && g.failures >= policy.BreakerThreshold {
+		g.openUntil = time.Now().Add(time.Duration(policy.BreakerCooldownSeconds) * time.Second)
+		slog.Debug("HTTP: Server keeps failing, failing fast", "host", g.host, "failures", g.failures, "until", g.openUntil)
+	}
+}
+
+// acquire waits for a turn under the rate limit and a free slot under the concurrency limit, returning
+// a function that frees the slot
+func (g *serverGuard) acquire(ctx context.Context, policy config.HTTPConfig) (func(), error) {
+	if policy.RateLimit > 0 {
+		g.mu.Lock()
+		start := time.Now()
+		if g.next.After(start) {
+			start = g.next
+		}
+		g.next = start.Add(time.Second / time.Duration(policy.RateLimit))
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Until(start)):
+		}
+	}
+
+	if policy.MaxConcurrent <= 0 {
+		return func() {}, nil
+	}
+	g.mu.Lock()
+	// A policy with another limit starts over, requests holding a slot free it in the old channel
+	if cap(g.slots) != policy.MaxConcurrent {
+		g.slots = make(chan struct{}, policy.MaxConcurrent)
+	}
+	slots := g.slots
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	}
+}
@@ -0,0 +1,171 @@
+package plex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/config"
+)
+
+// testServer answers the nth request with status(n), counting the requests
+func testServer(t *testing.T, status func(n int32) int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status(requests.Add(1)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func testClient(policy config.HTTPConfig) *http.Client {
+	return &http.Client{Transport: &policyTransport{base: http.DefaultTransport, policy: policy}}
+}
+
+func TestRetriesUnavailableServer(t *testing.T) {
+	srv, requests := testServer(t, func(n int32) int {
+		if n == 1 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+
+	res, err := testClient(config.HTTPConfig{Retries: 3, RetryBackoffMS: 1}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestDoesNotRetryPost(t *testing.T) {
+	srv, requests := testServer(t, func(int32) int { return http.StatusServiceUnavailable })
+
+	res, err := testClient(config.HTTPConfig{Retries: 3, RetryBackoffMS: 1}).Post(srv.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", res.StatusCode, http.StatusServiceUnavailable)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestBreakerFailsFast(t *testing.T) {
+	srv, requests := testServer(t, func(int32) int { return http.StatusBadGateway })
+	client := testClient(config.HTTPConfig{BreakerThreshold: 2, BreakerCooldownSeconds: 60})
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("request %d failed before the breaker opened: %v", i+1, err)
+		}
+		res.Body.Close()
+	}
+
+	_, err := client.Get(srv.URL)
+	if clierr.KindOf(err) != clierr.Unreachable {
+		t.Errorf("request with the breaker open = %v, want an unreachable error", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	tests := []struct {
+		retryAfter string
+		want       time.Duration
+	}{
+		{retryAfter: "0", want: 0},
+		{retryAfter: "5", want: 5 * time.Second},
+		{retryAfter: "3600", want: maxRetryBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.retryAfter, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", tt.retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := http.DefaultTransport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			transport := &policyTransport{policy: config.HTTPConfig{RetryBackoffMS: 1}}
+			retry, wait := transport.shouldRetry(req, res, nil, 0)
+			if !retry || wait != tt.want {
+				t.Errorf("shouldRetry = %v, %s, want true, %s", retry, wait, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotHeldUntilBodyClosed(t *testing.T) {
+	srv, _ := testServer(t, func(int32) int { return http.StatusOK })
+	client := testClient(config.HTTPConfig{MaxConcurrent: 1})
+
+	first, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("request while the first body is open = %v, want it to wait for the slot", err)
+	}
+
+	first.Body.Close()
+	first.Body.Close()
+	second, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request after the first body was closed failed: %v", err)
+	}
+	second.Body.Close()
+}
+
+func TestSlotsFollowPolicy(t *testing.T) {
+	srv, _ := testServer(t, func(int32) int { return http.StatusOK })
+
+	held, err := testClient(config.HTTPConfig{MaxConcurrent: 1}).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Body.Close()
+
+	for _, policy := range []config.HTTPConfig{{MaxConcurrent: 2}, {MaxConcurrent: 0}} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		res, err := testClient(policy).Do(req)
+		if err != nil {
+			t.Errorf("request with max_concurrent %d while a slot is held failed: %v", policy.MaxConcurrent, err)
+		} else {
+			res.Body.Close()
+		}
+		cancel()
+	}
+}
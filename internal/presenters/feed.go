@@ -0,0 +1,178 @@
+package presenters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ygelfand/plexctl/internal/timerange"
+)
+
+// FeedFormats are the formats a recently added digest can be written in
+var FeedFormats = []string{"markdown", "html", "rss"}
+
+// RecentFeed is a digest of recently added items, e.g. for a "new on Plex" email or feed
+type RecentFeed struct {
+	Title  string
+	Link   string
+	Added  timerange.Range
+	Groups []RecentGroup
+}
+
+// Write renders the digest in one of FeedFormats
+func (f RecentFeed) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return f.writeMarkdown(w)
+	case "html":
+		return recentHTML.Execute(w, f)
+	case "rss":
+		return f.writeRSS(w)
+	}
+	return fmt.Errorf("unknown format '%s' (allowed: %s)", format, strings.Join(FeedFormats, ", "))
+}
+
+// Description describes the period the digest covers
+func (f RecentFeed) Description() string {
+	return DescribeAdded(f.Added)
+}
+
+// DescribeAdded describes when items were added, e.g. "since 2026-01-02 10:00"
+func DescribeAdded(r timerange.Range) string {
+	const layout = "2006-01-02 15:04"
+	switch {
+	case r.To.IsZero():
+		return "since " + r.From.Format(layout)
+	case r.From.IsZero():
+		return "before " + r.To.Format(layout)
+	}
+	return fmt.Sprintf("between %s and %s", r.From.Format(layout), r.To.Format(layout))
+}
+
+// Single reports whether a group is just the item itself, such as a movie
+func (g RecentGroup) Single() bool {
+	return len(g.Items) == 1 && g.Items[0].ID == g.ID
+}
+
+func (f RecentFeed) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", f.Title)
+	fmt.Fprintf(&b, "_Added %s_\n", DescribeAdded(f.Added))
+	if len(f.Groups) == 0 {
+		b.WriteString("\nNothing new.\n")
+	}
+	for _, g := range f.Groups {
+		title := g.Title
+		if g.Link != "" {
+			title = fmt.Sprintf("[%s](%s)", g.Title, g.Link)
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		if g.Single() {
+			fmt.Fprintf(&b, "%s · added %s\n", g.Library, g.AddedAt.Format("2006-01-02"))
+			continue
+		}
+		for _, item := range g.Items {
+			fmt.Fprintf(&b, "- %s (%s)\n", item.Title, item.AddedAt.Format("2006-01-02"))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var recentHTML = template.Must(template.New("recent").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><em>Added {{.Description}}</em></p>
+{{- range .Groups}}
+<h2>{{if .Link}}<a href="{{.Link}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</h2>
+{{- if .Single}}
+<p>{{.Library}} · added {{.AddedAt.Format "2006-01-02"}}</p>
+{{- else}}
+<ul>
+{{- range .Items}}
+<li>{{.Title}} ({{.AddedAt.Format "2006-01-02"}})</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- else}}
+<p>Nothing new.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// writeRSS writes one feed item per group, identified by the group and its newest addition so
+// readers show a show again when new episodes arrive
+func (f RecentFeed) writeRSS(w io.Writer) error {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   "Added " + DescribeAdded(f.Added),
+		LastBuildDate: time.Now().Format(time.RFC1123Z),
+	}
+	for _, g := range f.Groups {
+		title := g.Title
+		var lines []string
+		if g.Single() {
+			lines = append(lines, g.Items[0].Summary)
+		} else {
+			title = fmt.Sprintf("%s: %d new", g.Title, len(g.Items))
+			for _, item := range g.Items {
+				lines = append(lines, item.Title)
+			}
+		}
+		channel.Items = append(channel.Items, rssItem{
+			Title:       title,
+			Link:        g.Link,
+			Description: strings.Join(lines, "\n"),
+			PubDate:     g.AddedAt.Format(time.RFC1123Z),
+			GUID:        rssGUID{Value: fmt.Sprintf("%s-%d", g.ID, g.AddedAt.Unix())},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(rssFeed{Version: "2.0", Channel: channel}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -2,7 +2,6 @@ package presenters
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -62,17 +61,17 @@ func (p *HistoryPresenter) SortableColumns() []string {
 func (p *HistoryPresenter) SortBy(column string) bool {
 	switch strings.ToLower(column) {
 	case "date":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Date > p.Items[j].Date })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.Date > b.Date })
 	case "user":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].User < p.Items[j].User })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.User < b.User })
 	case "title":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Title < p.Items[j].Title })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.Title < b.Title })
 	case "type":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Type < p.Items[j].Type })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.Type < b.Type })
 	case "device":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Device < p.Items[j].Device })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.Device < b.Device })
 	case "library":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Library < p.Items[j].Library })
+		sortWithRaw(p.Items, p.RawData, func(a, b HistoryItem) bool { return a.Library < b.Library })
 	default:
 		return false
 	}
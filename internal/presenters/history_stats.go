@@ -0,0 +1,150 @@
+package presenters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// HistoryGroupings are the ways playback history can be aggregated
+var HistoryGroupings = []string{"user", "library", "device", "day", "hour"}
+
+// HistoryStat is the playback activity of one user, library, device, day or hour. History entries
+// only record when an item was played, not how long or how far it was watched, so Runtime adds up the
+// full duration of the items played rather than the time spent watching, and there is no completion.
+type HistoryStat struct {
+	Key     string `json:"key"`
+	Plays   int    `json:"plays"`
+	Items   int    `json:"items"`
+	Runtime int64  `json:"runtime"` // milliseconds
+
+	seen map[string]bool
+}
+
+func (s *HistoryStat) add(ratingKey string, meta components.Metadata) {
+	s.Plays++
+	if !s.seen[ratingKey] {
+		s.seen[ratingKey] = true
+		s.Items++
+	}
+	s.Runtime += int64(ui.PtrToInt(meta.Duration))
+}
+
+// NewHistoryStats aggregates history entries by one of HistoryGroupings. Items are the mapped entries
+// in the same order as raw, and current is the metadata of the items played, used for their duration.
+// Items no longer on the server add no runtime.
+func NewHistoryStats(by string, items []HistoryItem, raw []operations.ListPlaybackHistoryMetadata, current map[string]components.Metadata) (stats []HistoryStat, total HistoryStat) {
+	groups := make(map[string]*HistoryStat)
+	total = HistoryStat{Key: "total", seen: make(map[string]bool)}
+
+	for i, item := range items {
+		var viewedAt time.Time
+		if raw[i].ViewedAt != nil {
+			viewedAt = time.Unix(*raw[i].ViewedAt, 0)
+		}
+
+		var key string
+		switch by {
+		case "library":
+			key = item.Library
+		case "device":
+			key = item.Device
+		case "day":
+			key = viewedAt.Format("2006-01-02")
+		case "hour":
+			key = fmt.Sprintf("%02d:00", viewedAt.Hour())
+		default:
+			key = item.User
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &HistoryStat{Key: key, seen: make(map[string]bool)}
+			groups[key] = g
+		}
+		ratingKey := ui.PtrToString(raw[i].RatingKey)
+		g.add(ratingKey, current[ratingKey])
+		total.add(ratingKey, current[ratingKey])
+	}
+
+	for _, g := range groups {
+		stats = append(stats, *g)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats, total
+}
+
+// HistoryStatsPresenter formats aggregated playback history with a bar chart of the plays
+type HistoryStatsPresenter struct {
+	By    string
+	Stats []HistoryStat
+	Total HistoryStat
+}
+
+func (p *HistoryStatsPresenter) Title() string {
+	return fmt.Sprintf("Playback by %s (%d plays, %s of runtime played)", p.By, p.Total.Plays, formatHours(p.Total.Runtime))
+}
+
+func (p *HistoryStatsPresenter) Headers() []string {
+	return []string{strings.ToUpper(p.By), "PLAYS", "ITEMS", "RUNTIME", "CHART"}
+}
+
+func (p *HistoryStatsPresenter) Rows() [][]string {
+	most := 0
+	for _, s := range p.Stats {
+		most = max(most, s.Plays)
+	}
+
+	var rows [][]string
+	for _, s := range p.Stats {
+		rows = append(rows, []string{
+			s.Key,
+			fmt.Sprintf("%d", s.Plays),
+			fmt.Sprintf("%d", s.Items),
+			formatHours(s.Runtime),
+			ui.Bar(float64(s.Plays)/float64(max(most, 1)), 30),
+		})
+	}
+	return rows
+}
+
+func (p *HistoryStatsPresenter) Raw() interface{} {
+	return map[string]any{
+		"by":    p.By,
+		"stats": p.Stats,
+		"total": p.Total,
+	}
+}
+
+func (p *HistoryStatsPresenter) SortableColumns() []string {
+	return []string{p.By, "plays", "items", "runtime"}
+}
+
+func (p *HistoryStatsPresenter) SortBy(column string) bool {
+	switch strings.ToLower(column) {
+	case p.By:
+		sort.SliceStable(p.Stats, func(i, j int) bool { return p.Stats[i].Key < p.Stats[j].Key })
+	case "plays":
+		sort.SliceStable(p.Stats, func(i, j int) bool { return p.Stats[i].Plays > p.Stats[j].Plays })
+	case "items":
+		sort.SliceStable(p.Stats, func(i, j int) bool { return p.Stats[i].Items > p.Stats[j].Items })
+	case "runtime":
+		sort.SliceStable(p.Stats, func(i, j int) bool { return p.Stats[i].Runtime > p.Stats[j].Runtime })
+	default:
+		return false
+	}
+	return true
+}
+
+// DefaultSort keeps days and hours in order and puts the busiest users, libraries and devices first
+func (p *HistoryStatsPresenter) DefaultSort() string {
+	if p.By == "day" || p.By == "hour" {
+		return p.By
+	}
+	return "plays"
+}
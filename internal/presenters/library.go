@@ -1,6 +1,7 @@
 package presenters
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -113,9 +114,23 @@ func (p *LibraryListPresenter) DefaultSort() string {
 
 // LibraryItemsPresenter formats items within a library
 type LibraryItemsPresenter struct {
-	SectionID string
-	Items     []GenericMetadata
-	RawData   interface{}
+	SectionID    string
+	Items        []GenericMetadata
+	RawData      interface{}
+	Fields       []string // columns to show, defaults to libraryItemFields
+	ServerSorted bool     // keep the order the server returned
+
+	// Pages fetches the items page by page for Stream and Collect
+	Pages func(ctx context.Context, page func([]components.Metadata) error) error
+}
+
+var libraryItemFields = []string{"id", "watched", "title", "type", "year", "duration", "rating", "content", "genre"}
+
+func (p *LibraryItemsPresenter) fields() []string {
+	if len(p.Fields) > 0 {
+		return p.Fields
+	}
+	return libraryItemFields
 }
 
 func (p *LibraryItemsPresenter) Title() string {
@@ -123,13 +138,24 @@ func (p *LibraryItemsPresenter) Title() string {
 }
 
 func (p *LibraryItemsPresenter) Headers() []string {
-	return []string{"ID", "WATCHED", "TITLE", "TYPE", "YEAR", "DURATION", "RATING", "CONTENT", "GENRE"}
+	var headers []string
+	for _, f := range p.fields() {
+		headers = append(headers, strings.ToUpper(f))
+	}
+	return headers
 }
 
 func (p *LibraryItemsPresenter) Rows() [][]string {
+	return p.rows(p.Items)
+}
+
+func (p *LibraryItemsPresenter) rows(items []GenericMetadata) [][]string {
 	var rows [][]string
-	for _, item := range p.Items {
-		row := append([]string{item.ID}, item.ToRow()...)
+	for _, item := range items {
+		var row []string
+		for _, f := range p.fields() {
+			row = append(row, item.Field(f))
+		}
 		rows = append(rows, row)
 	}
 	return rows
@@ -139,20 +165,46 @@ func (p *LibraryItemsPresenter) Raw() interface{} {
 	return p.RawData
 }
 
+func (p *LibraryItemsPresenter) Stream(ctx context.Context, page func(rows [][]string, raw []any) error) error {
+	return p.Pages(ctx, func(items []components.Metadata) error {
+		raw := make([]any, len(items))
+		for i := range items {
+			raw[i] = items[i]
+		}
+		return page(p.rows(MapMetadata(items)), raw)
+	})
+}
+
+func (p *LibraryItemsPresenter) Collect(ctx context.Context) error {
+	all := []components.Metadata{}
+	err := p.Pages(ctx, func(items []components.Metadata) error {
+		all = append(all, items...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	p.Items = MapMetadata(all)
+	p.RawData = all
+	return nil
+}
+
 func (p *LibraryItemsPresenter) SortableColumns() []string {
-	return []string{"id", "title", "year", "type"}
+	return []string{"id", "title", "year", "type", "added"}
 }
 
 func (p *LibraryItemsPresenter) SortBy(column string) bool {
 	switch strings.ToLower(column) {
 	case "id":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].ID < p.Items[j].ID })
+		sortWithRaw(p.Items, p.RawData, func(a, b GenericMetadata) bool { return a.ID < b.ID })
 	case "title":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Title < p.Items[j].Title })
+		sortWithRaw(p.Items, p.RawData, func(a, b GenericMetadata) bool { return a.Title < b.Title })
 	case "year":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Year < p.Items[j].Year })
+		sortWithRaw(p.Items, p.RawData, func(a, b GenericMetadata) bool { return a.Year < b.Year })
 	case "type":
-		sort.Slice(p.Items, func(i, j int) bool { return p.Items[i].Type < p.Items[j].Type })
+		sortWithRaw(p.Items, p.RawData, func(a, b GenericMetadata) bool { return a.Type < b.Type })
+	case "added":
+		sortWithRaw(p.Items, p.RawData, func(a, b GenericMetadata) bool { return a.AddedAt < b.AddedAt })
 	default:
 		return false
 	}
@@ -160,5 +212,8 @@ func (p *LibraryItemsPresenter) SortBy(column string) bool {
 }
 
 func (p *LibraryItemsPresenter) DefaultSort() string {
+	if p.ServerSorted {
+		return ""
+	}
 	return "title"
 }
@@ -2,7 +2,9 @@ package presenters
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/ygelfand/plexctl/internal/ui"
@@ -20,6 +22,49 @@ type GenericMetadata struct {
 	ContentRating string
 	Genre         string
 	Watched       string
+	AddedAt       string
+}
+
+// MetadataFields are the columns that can be selected for metadata listings
+var MetadataFields = []string{"id", "watched", "title", "type", "year", "duration", "rating", "content", "genre", "studio", "added"}
+
+// Field returns the value of one of MetadataFields
+func (m GenericMetadata) Field(name string) string {
+	switch strings.ToLower(name) {
+	case "id":
+		return m.ID
+	case "watched":
+		return m.Watched
+	case "title":
+		return m.Title
+	case "type":
+		return m.Type
+	case "year":
+		return m.Year
+	case "duration":
+		return m.Duration
+	case "rating":
+		return m.Rating
+	case "content":
+		return m.ContentRating
+	case "genre":
+		return m.Genre
+	case "studio":
+		return m.Studio
+	case "added":
+		return m.AddedAt
+	}
+	return ""
+}
+
+// ValidateFields checks that every field is one of MetadataFields
+func ValidateFields(fields []string) error {
+	for _, f := range fields {
+		if !slices.Contains(MetadataFields, strings.ToLower(f)) {
+			return fmt.Errorf("unknown field '%s' (allowed: %s)", f, strings.Join(MetadataFields, ", "))
+		}
+	}
+	return nil
 }
 
 // ToRow returns the metadata as a slice of strings for table rendering.
@@ -81,6 +126,11 @@ func MapMetadata(m []components.Metadata) []GenericMetadata {
 			genre = meta.Genre[0].Tag
 		}
 
+		added := ""
+		if meta.AddedAt > 0 {
+			added = time.Unix(meta.AddedAt, 0).Format("2006-01-02")
+		}
+
 		title := meta.Title
 		if meta.Type == "episode" {
 			parts := []string{}
@@ -109,6 +159,7 @@ func MapMetadata(m []components.Metadata) []GenericMetadata {
 			ContentRating: contentRating,
 			Genre:         genre,
 			Watched:       GetWatchedStatus(meta.ViewCount, meta.ViewOffset),
+			AddedAt:       added,
 		}
 	}
 	return res
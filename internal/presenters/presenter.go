@@ -1,5 +1,7 @@
 package presenters
 
+import "context"
+
 // Presenter defines how data should be formatted for output
 type Presenter interface {
 	// Title returns the title of the output (for table/text views)
@@ -23,3 +25,15 @@ type Presenter interface {
 	// DefaultSort returns the default sort column
 	DefaultSort() string
 }
+
+// StreamingPresenter is a Presenter whose data is fetched a page at a time, so it can be printed
+// while later pages are still being fetched
+type StreamingPresenter interface {
+	Presenter
+
+	// Stream fetches every page and calls page with its rows and raw items, without keeping them
+	Stream(ctx context.Context, page func(rows [][]string, raw []any) error) error
+
+	// Collect fetches every page into the presenter, so that it can be sorted and printed as a whole
+	Collect(ctx context.Context) error
+}
@@ -0,0 +1,267 @@
+package presenters
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SortKey is one column of a sort such as year:desc,title
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// RowFilter keeps the rows whose column equals (=), differs from (!=) or contains (~) a value,
+// ignoring case
+type RowFilter struct {
+	Column string
+	Op     string
+	Value  string
+}
+
+// RowQuery sorts, filters and limits the rows of any presenter by its column headers
+type RowQuery struct {
+	Sort    []SortKey
+	Filters []RowFilter
+	Limit   int
+}
+
+// ParseSort parses comma separated columns with an optional :asc or :desc, e.g. year:desc,title.
+// The second result reports whether it was a single column without a direction.
+func ParseSort(s string) ([]SortKey, bool, error) {
+	if s == "" {
+		return nil, false, nil
+	}
+	var keys []SortKey
+	plain := true
+	for _, part := range strings.Split(s, ",") {
+		column, dir, hasDir := strings.Cut(strings.TrimSpace(part), ":")
+		if column == "" {
+			return nil, false, fmt.Errorf("invalid sort '%s', expected columns like year:desc,title", s)
+		}
+		key := SortKey{Column: column}
+		switch strings.ToLower(dir) {
+		case "desc":
+			key.Desc = true
+		case "asc", "":
+		default:
+			return nil, false, fmt.Errorf("invalid sort direction '%s', expected asc or desc", dir)
+		}
+		plain = plain && !hasDir
+		keys = append(keys, key)
+	}
+	return keys, plain && len(keys) == 1, nil
+}
+
+// ParseFilters parses filters such as year=2020, type!=episode or title~star
+func ParseFilters(filters []string) ([]RowFilter, error) {
+	var parsed []RowFilter
+	for _, f := range filters {
+		i := strings.IndexAny(f, "=!~")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid filter '%s', expected column=value, column!=value or column~value", f)
+		}
+		filter := RowFilter{Column: f[:i]}
+		switch {
+		case strings.HasPrefix(f[i:], "!="):
+			filter.Op, filter.Value = "!=", f[i+2:]
+		case f[i] == '=' || f[i] == '~':
+			filter.Op, filter.Value = f[i:i+1], f[i+1:]
+		default:
+			return nil, fmt.Errorf("invalid filter '%s', expected column=value, column!=value or column~value", f)
+		}
+		parsed = append(parsed, filter)
+	}
+	return parsed, nil
+}
+
+func (f RowFilter) match(val string) bool {
+	switch f.Op {
+	case "!=":
+		return !strings.EqualFold(val, f.Value)
+	case "~":
+		return strings.Contains(strings.ToLower(val), strings.ToLower(f.Value))
+	default:
+		return strings.EqualFold(val, f.Value)
+	}
+}
+
+// Empty reports whether the query leaves the rows as they are
+func (q RowQuery) Empty() bool {
+	return len(q.Sort) == 0 && len(q.Filters) == 0 && q.Limit <= 0
+}
+
+// Filter keeps the rows, and raw items when there is one per row, that pass every filter
+func (q RowQuery) Filter(headers []string, rows [][]string, raw []any) ([][]string, []any, error) {
+	if len(q.Filters) == 0 {
+		return rows, raw, nil
+	}
+	columns := make([]int, len(q.Filters))
+	for i, f := range q.Filters {
+		col, err := columnIndex(headers, f.Column)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns[i] = col
+	}
+
+	var keptRows [][]string
+	var keptRaw []any
+	for i, row := range rows {
+		keep := true
+		for j, f := range q.Filters {
+			if !f.match(cell(row, columns[j])) {
+				keep = false
+				break
+			}
+		}
+		if !keep {
+			continue
+		}
+		keptRows = append(keptRows, row)
+		if raw != nil {
+			keptRaw = append(keptRaw, raw[i])
+		}
+	}
+	return keptRows, keptRaw, nil
+}
+
+// Apply filters, sorts and limits the rows, and raw items when there is one per row
+func (q RowQuery) Apply(headers []string, rows [][]string, raw []any) ([][]string, []any, error) {
+	rows, raw, err := q.Filter(headers, rows, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(q.Sort) > 0 {
+		columns := make([]int, len(q.Sort))
+		for i, key := range q.Sort {
+			col, err := columnIndex(headers, key.Column)
+			if err != nil {
+				return nil, nil, err
+			}
+			columns[i] = col
+		}
+
+		order := make([]int, len(rows))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			for i, key := range q.Sort {
+				c := compareNatural(cell(rows[order[a]], columns[i]), cell(rows[order[b]], columns[i]))
+				if c == 0 {
+					continue
+				}
+				return (c < 0) != key.Desc
+			}
+			return false
+		})
+
+		sortedRows := make([][]string, len(rows))
+		var sortedRaw []any
+		if raw != nil {
+			sortedRaw = make([]any, len(raw))
+		}
+		for i, j := range order {
+			sortedRows[i] = rows[j]
+			if raw != nil {
+				sortedRaw[i] = raw[j]
+			}
+		}
+		rows, raw = sortedRows, sortedRaw
+	}
+
+	if q.Limit > 0 && len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		if raw != nil {
+			raw = raw[:q.Limit]
+		}
+	}
+	return rows, raw, nil
+}
+
+// columnIndex finds a column by its header ignoring case, with spaces in headers also matching - or _
+func columnIndex(headers []string, column string) (int, error) {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+	}
+	var names []string
+	for i, h := range headers {
+		if normalize(h) == normalize(column) {
+			return i, nil
+		}
+		names = append(names, strings.ReplaceAll(strings.ToLower(h), " ", "_"))
+	}
+	return 0, fmt.Errorf("unknown column '%s' (available: %s)", column, strings.Join(names, ", "))
+}
+
+func cell(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// compareNatural compares strings ignoring case, with runs of digits compared by value so that
+// 9 sorts before 10
+func compareNatural(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	i, j := 0, 0
+	for i < len(ra) && j < len(rb) {
+		if unicode.IsDigit(ra[i]) && unicode.IsDigit(rb[j]) {
+			si := i
+			for i < len(ra) && unicode.IsDigit(ra[i]) {
+				i++
+			}
+			sj := j
+			for j < len(rb) && unicode.IsDigit(rb[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ra[si:i]), "0")
+			nb := strings.TrimLeft(string(rb[sj:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			continue
+		}
+		ca, cb := unicode.ToLower(ra[i]), unicode.ToLower(rb[j])
+		if ca != cb {
+			return int(ca) - int(cb)
+		}
+		i++
+		j++
+	}
+	return (len(ra) - i) - (len(rb) - j)
+}
+
+// sortWithRaw sorts items and reorders raw the same way when it is a separate list of the same length,
+// so that rows and raw data stay in step for -o json and --filter
+func sortWithRaw[T any](items []T, raw any, less func(a, b T) bool) {
+	swapItems := reflect.Swapper(items)
+	swapRaw := func(i, j int) {}
+	if v := reflect.ValueOf(raw); v.Kind() == reflect.Slice && v.Len() == len(items) {
+		swapRaw = reflect.Swapper(raw)
+	}
+	sort.Sort(pairedSort{
+		n:    len(items),
+		less: func(i, j int) bool { return less(items[i], items[j]) },
+		swap: func(i, j int) { swapItems(i, j); swapRaw(i, j) },
+	})
+}
+
+type pairedSort struct {
+	n    int
+	less func(i, j int) bool
+	swap func(i, j int)
+}
+
+func (s pairedSort) Len() int           { return s.n }
+func (s pairedSort) Less(i, j int) bool { return s.less(i, j) }
+func (s pairedSort) Swap(i, j int)      { s.swap(i, j) }
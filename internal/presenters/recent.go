@@ -0,0 +1,117 @@
+package presenters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// RecentItem is a single recently added item
+type RecentItem struct {
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Type    string    `json:"type"`
+	Library string    `json:"library"`
+	AddedAt time.Time `json:"added_at"`
+	Summary string    `json:"summary,omitempty"`
+}
+
+// RecentGroup is a show, artist or single item with everything added to it, newest first
+type RecentGroup struct {
+	ID      string       `json:"id"`
+	Title   string       `json:"title"`
+	Library string       `json:"library"`
+	AddedAt time.Time    `json:"added_at"` // when the newest item was added
+	Link    string       `json:"link,omitempty"`
+	Items   []RecentItem `json:"items"`
+}
+
+// GroupRecent groups items added to the same show or artist, ordered by the newest addition.
+// Items are expected to be sorted newest first.
+func GroupRecent(library string, items []components.Metadata) []RecentGroup {
+	var groups []RecentGroup
+	index := make(map[string]int)
+
+	for _, meta := range items {
+		id, title := ui.PtrToString(meta.RatingKey), meta.Title
+		if meta.Year != nil {
+			title = fmt.Sprintf("%s (%d)", title, *meta.Year)
+		}
+		switch meta.Type {
+		case "episode", "track":
+			id, title = ui.PtrToString(meta.GrandparentRatingKey), ui.PtrToString(meta.GrandparentTitle)
+		case "season", "album":
+			id, title = ui.PtrToString(meta.ParentRatingKey), ui.PtrToString(meta.ParentTitle)
+		}
+
+		item := RecentItem{
+			ID:      ui.PtrToString(meta.RatingKey),
+			Title:   recentItemTitle(meta),
+			Type:    meta.Type,
+			Library: library,
+			AddedAt: time.Unix(meta.AddedAt, 0),
+			Summary: ui.PtrToString(meta.Summary),
+		}
+
+		i, ok := index[id]
+		if !ok || id == "" {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, RecentGroup{ID: id, Title: title, Library: library, AddedAt: item.AddedAt})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+	return groups
+}
+
+// recentItemTitle is the title of an item within its group
+func recentItemTitle(meta components.Metadata) string {
+	switch meta.Type {
+	case "episode":
+		return fmt.Sprintf("S%02dE%02d · %s", ui.PtrToInt(meta.ParentIndex), ui.PtrToInt(meta.Index), meta.Title)
+	case "track":
+		return fmt.Sprintf("%s · %s", ui.PtrToString(meta.ParentTitle), meta.Title)
+	}
+	return meta.Title
+}
+
+// RecentPresenter lists recently added items, one row per item under its show or artist
+type RecentPresenter struct {
+	Groups []RecentGroup
+}
+
+func (p *RecentPresenter) Title() string {
+	return "Recently Added"
+}
+
+func (p *RecentPresenter) Headers() []string {
+	return []string{"ADDED", "LIBRARY", "GROUP", "TITLE", "TYPE", "ID"}
+}
+
+func (p *RecentPresenter) Rows() [][]string {
+	var rows [][]string
+	for _, g := range p.Groups {
+		for _, item := range g.Items {
+			rows = append(rows, []string{item.AddedAt.Format("2006-01-02 15:04"), item.Library, g.Title, item.Title, item.Type, item.ID})
+		}
+	}
+	return rows
+}
+
+func (p *RecentPresenter) Raw() interface{} {
+	return p.Groups
+}
+
+func (p *RecentPresenter) SortableColumns() []string {
+	return nil
+}
+
+func (p *RecentPresenter) SortBy(column string) bool {
+	return false
+}
+
+func (p *RecentPresenter) DefaultSort() string {
+	return ""
+}
@@ -198,15 +198,15 @@ func (p *SessionsPresenter) SortableColumns() []string {
 func (p *SessionsPresenter) SortBy(column string) bool {
 	switch strings.ToLower(column) {
 	case "id":
-		sort.Slice(p.Sessions, func(i, j int) bool { return p.Sessions[i].ID < p.Sessions[j].ID })
+		sortWithRaw(p.Sessions, p.RawData, func(a, b SessionMetadata) bool { return a.ID < b.ID })
 	case "user":
-		sort.Slice(p.Sessions, func(i, j int) bool { return p.Sessions[i].User < p.Sessions[j].User })
+		sortWithRaw(p.Sessions, p.RawData, func(a, b SessionMetadata) bool { return a.User < b.User })
 	case "player":
-		sort.Slice(p.Sessions, func(i, j int) bool { return p.Sessions[i].Player < p.Sessions[j].Player })
+		sortWithRaw(p.Sessions, p.RawData, func(a, b SessionMetadata) bool { return a.Player < b.Player })
 	case "title":
-		sort.Slice(p.Sessions, func(i, j int) bool { return p.Sessions[i].Title < p.Sessions[j].Title })
+		sortWithRaw(p.Sessions, p.RawData, func(a, b SessionMetadata) bool { return a.Title < b.Title })
 	case "state":
-		sort.Slice(p.Sessions, func(i, j int) bool { return p.Sessions[i].State < p.Sessions[j].State })
+		sortWithRaw(p.Sessions, p.RawData, func(a, b SessionMetadata) bool { return a.State < b.State })
 	default:
 		return false
 	}
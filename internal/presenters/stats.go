@@ -0,0 +1,248 @@
+package presenters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// Bitrate buckets in kbps, the last one is open ended
+var bitrateBuckets = []struct {
+	Max   int
+	Label string
+}{
+	{2000, "< 2 Mbps"},
+	{5000, "2-5 Mbps"},
+	{10000, "5-10 Mbps"},
+	{20000, "10-20 Mbps"},
+	{40000, "20-40 Mbps"},
+	{0, "40+ Mbps"},
+}
+
+// StatsBucket is the share of a library with a given property, e.g. a codec
+type StatsBucket struct {
+	Value    string `json:"value"`
+	Count    int    `json:"count"`
+	Size     int64  `json:"size"`
+	Duration int64  `json:"duration"`
+}
+
+// StatsItem is a single media version, used for the largest items
+type StatsItem struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Resolution string `json:"resolution"`
+	VideoCodec string `json:"video_codec"`
+	Size       int64  `json:"size"`
+}
+
+// LibraryStats is an inventory of the media in one or more libraries. Every media version is counted,
+// so items with several versions contribute each of them.
+type LibraryStats struct {
+	Libraries []string                 `json:"libraries"`
+	Items     int                      `json:"items"`
+	Versions  int                      `json:"versions"`
+	Size      int64                    `json:"size"`
+	Duration  int64                    `json:"duration"`
+	Breakdown map[string][]StatsBucket `json:"breakdown"`
+	Largest   []StatsItem              `json:"largest"`
+
+	buckets map[string]map[string]*StatsBucket
+	items   []StatsItem
+}
+
+// Stats categories in the order they are reported
+var statsCategories = []string{"resolution", "video_codec", "audio_codec", "container", "dynamic_range", "bitrate"}
+
+// NewLibraryStats creates an empty inventory
+func NewLibraryStats() *LibraryStats {
+	return &LibraryStats{
+		Breakdown: make(map[string][]StatsBucket),
+		buckets:   make(map[string]map[string]*StatsBucket),
+	}
+}
+
+// Add counts the media of the given items under a library
+func (s *LibraryStats) Add(library string, items []components.Metadata) {
+	s.Libraries = append(s.Libraries, library)
+	for _, meta := range items {
+		s.Items++
+		title := MapMetadata([]components.Metadata{meta})[0].Title
+		for _, media := range meta.Media {
+			s.addMedia(ui.PtrToString(meta.RatingKey), title, media)
+		}
+	}
+}
+
+func (s *LibraryStats) addMedia(id, title string, media components.Media) {
+	var size int64
+	for _, part := range media.Part {
+		if part.Size != nil {
+			size += *part.Size
+		}
+	}
+	duration := int64(0)
+	if media.Duration != nil {
+		duration = int64(*media.Duration)
+	}
+
+	s.Versions++
+	s.Size += size
+	s.Duration += duration
+
+	resolution := ui.PtrToString(media.VideoResolution)
+	videoCodec := ui.PtrToString(media.VideoCodec)
+	values := map[string]string{
+		"resolution":    resolution,
+		"video_codec":   videoCodec,
+		"audio_codec":   ui.PtrToString(media.AudioCodec),
+		"container":     ui.PtrToString(media.Container),
+		"dynamic_range": dynamicRange(media),
+		"bitrate":       bitrateBucket(media.Bitrate),
+	}
+	for category, value := range values {
+		if value == "" {
+			continue
+		}
+		if s.buckets[category] == nil {
+			s.buckets[category] = make(map[string]*StatsBucket)
+		}
+		b, ok := s.buckets[category][value]
+		if !ok {
+			b = &StatsBucket{Value: value}
+			s.buckets[category][value] = b
+		}
+		b.Count++
+		b.Size += size
+		b.Duration += duration
+	}
+
+	s.items = append(s.items, StatsItem{ID: id, Title: title, Resolution: resolution, VideoCodec: videoCodec, Size: size})
+}
+
+// Finish sorts the breakdowns by size and keeps the top largest items
+func (s *LibraryStats) Finish(top int) {
+	for category, buckets := range s.buckets {
+		list := make([]StatsBucket, 0, len(buckets))
+		for _, b := range buckets {
+			list = append(list, *b)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Size != list[j].Size {
+				return list[i].Size > list[j].Size
+			}
+			return list[i].Count > list[j].Count
+		})
+		s.Breakdown[category] = list
+	}
+
+	sort.Slice(s.items, func(i, j int) bool { return s.items[i].Size > s.items[j].Size })
+	s.Largest = s.items[:min(top, len(s.items))]
+}
+
+// dynamicRange reads HDR information from the video stream when the server included it,
+// otherwise only a 10-bit profile can be told apart from SDR
+func dynamicRange(media components.Media) string {
+	for _, part := range media.Part {
+		for _, stream := range part.Stream {
+			if stream.StreamType != components.StreamTypeVideo {
+				continue
+			}
+			if stream.DOVIPresent != nil && *stream.DOVIPresent {
+				return "Dolby Vision"
+			}
+			switch ui.PtrToString(stream.ColorTrc) {
+			case "smpte2084", "arib-std-b67":
+				return "HDR"
+			}
+			return "SDR"
+		}
+	}
+	if media.VideoCodec == nil {
+		return ""
+	}
+	if strings.Contains(strings.ToLower(ui.PtrToString(media.VideoProfile)), "10") {
+		return "10-bit"
+	}
+	return "SDR"
+}
+
+func bitrateBucket(bitrate *int) string {
+	if bitrate == nil || *bitrate <= 0 {
+		return ""
+	}
+	for _, b := range bitrateBuckets {
+		if b.Max == 0 || *bitrate < b.Max {
+			return b.Label
+		}
+	}
+	return ""
+}
+
+// LibraryStatsPresenter formats a library inventory as one table of categories
+type LibraryStatsPresenter struct {
+	Stats *LibraryStats
+}
+
+func (p *LibraryStatsPresenter) Title() string {
+	return fmt.Sprintf("Library Stats (%s)", strings.Join(p.Stats.Libraries, ", "))
+}
+
+func (p *LibraryStatsPresenter) Headers() []string {
+	return []string{"CATEGORY", "VALUE", "COUNT", "SIZE", "DURATION", "SHARE"}
+}
+
+func (p *LibraryStatsPresenter) Rows() [][]string {
+	s := p.Stats
+	rows := [][]string{
+		{"total", "items", fmt.Sprintf("%d", s.Items), "", "", ""},
+		{"total", "versions", fmt.Sprintf("%d", s.Versions), ui.FormatBytes(s.Size), formatHours(s.Duration), "100%"},
+	}
+	for _, category := range statsCategories {
+		for _, b := range s.Breakdown[category] {
+			rows = append(rows, []string{category, b.Value, fmt.Sprintf("%d", b.Count), ui.FormatBytes(b.Size), formatHours(b.Duration), share(b.Size, s.Size)})
+		}
+	}
+	for _, item := range s.Largest {
+		rows = append(rows, []string{"largest", fmt.Sprintf("%s (%s)", item.Title, item.ID), "1", ui.FormatBytes(item.Size), "", share(item.Size, s.Size)})
+	}
+	return rows
+}
+
+func (p *LibraryStatsPresenter) Raw() interface{} {
+	return p.Stats
+}
+
+func (p *LibraryStatsPresenter) SortableColumns() []string {
+	return nil
+}
+
+func (p *LibraryStatsPresenter) SortBy(column string) bool {
+	return false
+}
+
+func (p *LibraryStatsPresenter) DefaultSort() string {
+	return ""
+}
+
+// formatHours formats a duration in milliseconds as whole hours, which reads better for libraries than h:mm:ss
+func formatHours(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	hours := float64(ms) / 3600000
+	if hours < 1 {
+		return fmt.Sprintf("%.0fm", hours*60)
+	}
+	return fmt.Sprintf("%.0fh", hours)
+}
+
+func share(part, total int64) string {
+	if total <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
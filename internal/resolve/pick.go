@@ -0,0 +1,221 @@
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/search"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// PosterRenderer draws the poster of an item width cells wide, returning an empty string for items
+// without one
+type PosterRenderer func(ctx context.Context, meta components.Metadata, width int) (string, error)
+
+var renderPoster PosterRenderer
+
+// SetPosterRenderer sets how previews draw posters, leaving the drawing to the TUI. Without one the
+// previews show just the details.
+func SetPosterRenderer(r PosterRenderer) {
+	renderPoster = r
+}
+
+// List returns everything of a kind. Library items and shows come from the local search index.
+func List(ctx context.Context, client *plex.Client, kind Kind) ([]Candidate, error) {
+	switch kind {
+	case Library:
+		sections, err := allSections(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		var candidates []Candidate
+		for _, d := range sections {
+			candidates = append(candidates, Candidate{ID: ui.PtrToString(d.Key), Title: ui.PtrToString(d.Title), Type: string(d.Type)})
+		}
+		return candidates, nil
+	case Playlist:
+		return playlists(ctx, client, "")
+	case Collection:
+		return collections(ctx, client)
+	default:
+		var candidates []Candidate
+		for _, e := range search.GetIndex().Entries {
+			if kind.accepts(e.Type) {
+				candidates = append(candidates, Candidate{ID: e.RatingKey, Title: e.Title, Type: e.Type, Year: e.Year, Library: e.Library})
+			}
+		}
+		return candidates, nil
+	}
+}
+
+// Pick asks the user to choose one or, with multi, several of everything of a kind, previewing the
+// highlighted one. It fails with ui.ErrNoTerminal when there is no terminal to ask on.
+func Pick(ctx context.Context, client *plex.Client, kind Kind, multi bool) ([]string, error) {
+	if !ui.Interactive() {
+		return nil, ui.ErrNoTerminal
+	}
+
+	candidates, err := List(ctx, client, kind)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		if kind == Item || kind == Show {
+			return nil, clierr.New(clierr.NotFound, "the search index has no %s, run 'plexctl search reindex' or pass an ID", kind.plural())
+		}
+		return nil, clierr.New(clierr.NotFound, "no %s found", kind.plural())
+	}
+
+	preview := func(id string) string { return previewItem(ctx, id) }
+	if kind == Library {
+		preview = func(id string) string { return previewLibrary(ctx, client, id) }
+	}
+
+	title := fmt.Sprintf("Select a %s", kind)
+	if multi {
+		title = fmt.Sprintf("Select %s", kind.plural())
+	}
+	return pickFrom(title, candidates, multi, preview)
+}
+
+// pickFrom runs a picker over candidates and returns the IDs chosen
+func pickFrom(title string, candidates []Candidate, multi bool, preview func(id string) string) ([]string, error) {
+	items := make([]ui.PickerItem, len(candidates))
+	for i, c := range candidates {
+		items[i] = ui.PickerItem{Title: c.Label(), Desc: c.detail(), Value: c.ID}
+	}
+	return ui.Picker{Title: title, Items: items, Multi: multi, Preview: preview}.Run()
+}
+
+// runLists keeps the libraries and playlists of each client for the rest of the run, as every reference
+// to one would fetch them again
+var (
+	runListsMu   sync.Mutex
+	runSections  = make(map[*plex.Client][]components.LibrarySection)
+	runPlaylists = make(map[*plex.Client][]Candidate)
+)
+
+func allSections(ctx context.Context, client *plex.Client) ([]components.LibrarySection, error) {
+	runListsMu.Lock()
+	defer runListsMu.Unlock()
+	if sections, ok := runSections[client]; ok {
+		return sections, nil
+	}
+
+	slog.Debug("SDK: Fetching sections")
+	res, err := client.SDK.Library.GetSections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	var sections []components.LibrarySection
+	if res.Object != nil && res.Object.MediaContainer != nil {
+		sections = res.Object.MediaContainer.Directory
+	}
+	runSections[client] = sections
+	return sections, nil
+}
+
+// collections lists the collections of every library
+func collections(ctx context.Context, client *plex.Client) ([]Candidate, error) {
+	sections, err := allSections(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	for _, d := range sections {
+		sectionID, err := strconv.ParseInt(ui.PtrToString(d.Key), 10, 64)
+		if err != nil {
+			continue
+		}
+		slog.Debug("SDK: Fetching collections", "library_id", sectionID)
+		res, err := client.SDK.Library.GetCollections(ctx, operations.GetCollectionsRequest{SectionID: sectionID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list collections: %w", err)
+		}
+		if res.MediaContainerWithMetadata == nil || res.MediaContainerWithMetadata.MediaContainer == nil {
+			continue
+		}
+		for _, m := range res.MediaContainerWithMetadata.MediaContainer.Metadata {
+			if m.RatingKey != nil {
+				candidates = append(candidates, Candidate{ID: *m.RatingKey, Title: m.Title, Type: "collection", Library: ui.PtrToString(d.Title)})
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// previewItem renders the poster and details of a library item, collection or playlist
+func previewItem(ctx context.Context, ratingKey string) string {
+	meta, err := plex.GetMetadata(ctx, ratingKey, false)
+	if err != nil {
+		return ui.ErrorStyle(ui.CurrentTheme()).Render(err.Error())
+	}
+
+	theme := ui.CurrentTheme()
+	var facts []string
+	facts = append(facts, meta.Type)
+	if meta.Year != nil {
+		facts = append(facts, fmt.Sprintf("%d", *meta.Year))
+	}
+	if meta.Duration != nil && *meta.Duration > 0 {
+		facts = append(facts, ui.FormatDuration(*meta.Duration))
+	}
+	if meta.ContentRating != nil {
+		facts = append(facts, *meta.ContentRating)
+	}
+
+	lines := []string{
+		ui.TitleStyle(theme).MarginBottom(0).Render(meta.Title),
+		ui.ValueStyle(theme).Render(strings.Join(facts, " · ")),
+	}
+	if meta.GrandparentTitle != nil {
+		lines = append(lines, ui.ValueStyle(theme).Render(*meta.GrandparentTitle))
+	}
+	if meta.Summary != nil && *meta.Summary != "" {
+		lines = append(lines, "", ui.ValueStyle(theme).Render(*meta.Summary))
+	}
+
+	// A poster that fails to load leaves just the details
+	var view string
+	if renderPoster != nil {
+		view, _ = renderPoster(ctx, *meta, ui.PosterWidth)
+	}
+	if view == "" {
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append([]string{view, ""}, lines...)...)
+}
+
+// previewLibrary renders the details of a library
+func previewLibrary(ctx context.Context, client *plex.Client, id string) string {
+	theme := ui.CurrentTheme()
+	sections, err := allSections(ctx, client)
+	if err != nil {
+		return ui.ErrorStyle(theme).Render(err.Error())
+	}
+	for _, d := range sections {
+		if ui.PtrToString(d.Key) != id {
+			continue
+		}
+		lines := []string{
+			ui.TitleStyle(theme).MarginBottom(0).Render(ui.PtrToString(d.Title)),
+			ui.ValueStyle(theme).Render(string(d.Type)),
+		}
+		for _, loc := range d.Location {
+			if path, ok := loc.Path.(string); ok {
+				lines = append(lines, ui.ValueStyle(theme).Render(path))
+			}
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, lines...)
+	}
+	return ""
+}
@@ -0,0 +1,375 @@
+// Package resolve turns the references people type, such as "The Matrix (1999)", "Breaking Bad S02E05",
+// library names and plex:// GUIDs, into the IDs the server expects.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LukeHagar/plexgo/models/components"
+	"github.com/LukeHagar/plexgo/models/operations"
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/search"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// Kind is what a reference names
+type Kind string
+
+const (
+	Item       Kind = "item"
+	Show       Kind = "show"
+	Collection Kind = "collection"
+	Playlist   Kind = "playlist"
+	Library    Kind = "library"
+)
+
+// itemTypes are the types of library items an Item reference can name
+var itemTypes = []string{"movie", "show", "season", "episode", "artist", "album", "track", "photo", "clip"}
+
+// searchLimit caps the results per hub of a server search
+const searchLimit = 20
+
+var (
+	episodeRef = regexp.MustCompile(`(?i)^(.+?)\s+s(\d{1,3})\s*e(\d{1,4})$`)
+	yearRef    = regexp.MustCompile(`^(.+?)\s*\((\d{4})\)$`)
+	guidRef    = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://`)
+)
+
+// Options control how a reference matching several items is settled
+type Options struct {
+	// First picks the best match instead of asking, for scripts
+	First bool
+
+	// IDs takes numbers as IDs without checking they exist, for keys read from stdin
+	IDs bool
+}
+
+// Candidate is an item a reference may name
+type Candidate struct {
+	ID      string
+	Title   string
+	Type    string
+	Year    int
+	Library string
+
+	original string
+}
+
+// Label is the title of the candidate with its year, as it can be given to Resolve
+func (c Candidate) Label() string {
+	if c.Year > 0 {
+		return fmt.Sprintf("%s (%d)", c.Title, c.Year)
+	}
+	return c.Title
+}
+
+func (c Candidate) detail() string {
+	parts := []string{c.Type}
+	if c.Library != "" {
+		parts = append(parts, c.Library)
+	}
+	parts = append(parts, "ID "+c.ID)
+	return strings.Join(parts, " - ")
+}
+
+// Resolve returns the ID a reference names. Numbers are IDs when an item, library or playlist with that
+// ID exists or opts.IDs is set, and titles otherwise, such as 1917. Other references are looked up in the local search
+// index first and on the server when the index has no match.
+// When several items match, the user picks one in a terminal, otherwise --first picks the best match
+// or an error lists them.
+func Resolve(ctx context.Context, client *plex.Client, kind Kind, ref string, opts Options) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", clierr.New(clierr.Usage, "empty %s reference", kind)
+	}
+	if isID(ref) && opts.IDs {
+		return ref, nil
+	}
+	if isID(ref) {
+		exists, err := idExists(ctx, client, kind, ref)
+		if exists || clierr.KindOf(err) != clierr.NotFound {
+			// Failures other than a missing ID are left to the command using it
+			return ref, nil
+		}
+		slog.Debug("Resolve: No such ID, looking for a title", "kind", kind, "ref", ref)
+	}
+
+	var candidates []Candidate
+	var err error
+	switch kind {
+	case Library:
+		candidates, err = libraries(ctx, client, ref)
+	case Playlist:
+		candidates, err = playlists(ctx, client, ref)
+	default:
+		if m := episodeRef.FindStringSubmatch(ref); m != nil && kind == Item {
+			return episode(ctx, client, m[1], m[2], m[3], opts)
+		}
+		candidates, err = items(ctx, client, kind, ref)
+	}
+	if err != nil {
+		return "", err
+	}
+	slog.Debug("Resolve: Found candidates", "kind", kind, "ref", ref, "count", len(candidates))
+
+	c, err := pick(ctx, kind, ref, candidates, opts)
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func isID(ref string) bool {
+	_, err := strconv.ParseUint(ref, 10, 64)
+	return err == nil
+}
+
+// idExists reports whether something of the kind has the ID, failing with a clierr.NotFound error when
+// it does not
+func idExists(ctx context.Context, client *plex.Client, kind Kind, id string) (bool, error) {
+	var candidates []Candidate
+	var err error
+	switch kind {
+	case Library:
+		var sections []components.LibrarySection
+		sections, err = allSections(ctx, client)
+		for _, d := range sections {
+			candidates = append(candidates, Candidate{ID: ui.PtrToString(d.Key)})
+		}
+	case Playlist:
+		candidates, err = playlists(ctx, client, "")
+	default:
+		for _, e := range search.GetIndex().Entries {
+			if e.RatingKey == id {
+				return true, nil
+			}
+		}
+		if _, err := plex.GetMetadata(ctx, id, false); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if slices.ContainsFunc(candidates, func(c Candidate) bool { return c.ID == id }) {
+		return true, nil
+	}
+	return false, clierr.New(clierr.NotFound, "no %s with ID %s", kind, id)
+}
+
+// accepts reports whether an item of the given type can be named by a reference of this kind
+func (k Kind) accepts(itemType string) bool {
+	switch k {
+	case Item:
+		for _, t := range itemTypes {
+			if t == itemType {
+				return true
+			}
+		}
+		return false
+	default:
+		return itemType == string(k)
+	}
+}
+
+func (k Kind) plural() string {
+	if k == Library {
+		return "libraries"
+	}
+	return string(k) + "s"
+}
+
+// items finds library items by GUID, or by title with an optional year
+func items(ctx context.Context, client *plex.Client, kind Kind, ref string) ([]Candidate, error) {
+	if guidRef.MatchString(ref) {
+		found, err := plex.FindByGUID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		return fromMetadata(kind, found), nil
+	}
+
+	title, year := ref, 0
+	if m := yearRef.FindStringSubmatch(ref); m != nil {
+		title = m[1]
+		year, _ = strconv.Atoi(m[2])
+	}
+	matches := func(c Candidate) bool {
+		return (strings.EqualFold(c.Title, title) || strings.EqualFold(c.original, title)) && (year == 0 || c.Year == year)
+	}
+
+	var candidates []Candidate
+	for _, e := range search.GetIndex().Entries {
+		c := Candidate{ID: e.RatingKey, Title: e.Title, Type: e.Type, Year: e.Year, Library: e.Library, original: e.OriginalTitle}
+		if kind.accepts(e.Type) && matches(c) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+
+	slog.Debug("SDK: Searching server", "query", title)
+	res, err := client.SDK.Search.SearchHubs(ctx, operations.SearchHubsRequest{
+		Query: title,
+		Limit: ui.Ptr(int64(searchLimit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for '%s': %w", title, err)
+	}
+	var found []components.Metadata
+	if res.Object != nil && res.Object.MediaContainer != nil {
+		for _, hub := range res.Object.MediaContainer.Hub {
+			found = append(found, hub.Metadata...)
+		}
+	}
+
+	// Prefer exact titles, and fall back to whatever the server considers a match
+	all := fromMetadata(kind, found)
+	for _, c := range all {
+		if matches(c) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) > 0 || year > 0 {
+		return candidates, nil
+	}
+	return all, nil
+}
+
+// fromMetadata turns the items of the kind into candidates
+func fromMetadata(kind Kind, found []components.Metadata) []Candidate {
+	var candidates []Candidate
+	for _, m := range found {
+		if !kind.accepts(m.Type) || m.RatingKey == nil {
+			continue
+		}
+		library, _ := m.AdditionalProperties["librarySectionTitle"].(string)
+		candidates = append(candidates, Candidate{
+			ID:       *m.RatingKey,
+			Title:    m.Title,
+			Type:     m.Type,
+			Year:     ui.PtrToInt(m.Year),
+			Library:  library,
+			original: ui.PtrToString(m.OriginalTitle),
+		})
+	}
+	return candidates
+}
+
+// episode finds an episode of a show by its season and episode number
+func episode(ctx context.Context, client *plex.Client, showRef, season, number string, opts Options) (string, error) {
+	showKey, err := Resolve(ctx, client, Show, showRef, opts)
+	if err != nil {
+		return "", err
+	}
+	s, _ := strconv.Atoi(season)
+	e, _ := strconv.Atoi(number)
+
+	slog.Debug("SDK: Fetching episodes", "show", showKey)
+	res, err := client.SDK.Library.GetAllItemLeaves(ctx, operations.GetAllItemLeavesRequest{Ids: showKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to list episodes: %w", err)
+	}
+	if res.MediaContainerWithMetadata != nil && res.MediaContainerWithMetadata.MediaContainer != nil {
+		for _, ep := range res.MediaContainerWithMetadata.MediaContainer.Metadata {
+			if ui.PtrToInt(ep.ParentIndex) == s && ui.PtrToInt(ep.Index) == e && ep.RatingKey != nil {
+				return *ep.RatingKey, nil
+			}
+		}
+	}
+	return "", clierr.New(clierr.NotFound, "%s has no episode S%02dE%02d", showRef, s, e)
+}
+
+// libraries finds libraries by name
+func libraries(ctx context.Context, client *plex.Client, ref string) ([]Candidate, error) {
+	sections, err := allSections(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	for _, d := range sections {
+		if strings.EqualFold(ui.PtrToString(d.Title), ref) {
+			candidates = append(candidates, Candidate{ID: ui.PtrToString(d.Key), Title: ui.PtrToString(d.Title), Type: string(d.Type)})
+		}
+	}
+	return candidates, nil
+}
+
+// playlists finds playlists by name, or lists them all when name is empty
+func playlists(ctx context.Context, client *plex.Client, name string) ([]Candidate, error) {
+	all, err := allPlaylists(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	for _, c := range all {
+		if name == "" || strings.EqualFold(c.Title, name) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+func allPlaylists(ctx context.Context, client *plex.Client) ([]Candidate, error) {
+	runListsMu.Lock()
+	defer runListsMu.Unlock()
+	if candidates, ok := runPlaylists[client]; ok {
+		return candidates, nil
+	}
+
+	slog.Debug("SDK: Fetching playlists")
+	res, err := client.SDK.Playlist.ListPlaylists(ctx, operations.ListPlaylistsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+	var candidates []Candidate
+	if res.MediaContainerWithPlaylistMetadata != nil && res.MediaContainerWithPlaylistMetadata.MediaContainer != nil {
+		for _, p := range res.MediaContainerWithPlaylistMetadata.MediaContainer.Metadata {
+			if p.RatingKey != nil {
+				candidates = append(candidates, Candidate{ID: *p.RatingKey, Title: p.Title, Type: "playlist"})
+			}
+		}
+	}
+	runPlaylists[client] = candidates
+	return candidates, nil
+}
+
+// pick settles on one candidate, asking the user when there are several and a terminal to ask on
+func pick(ctx context.Context, kind Kind, ref string, valid []Candidate, opts Options) (Candidate, error) {
+	switch {
+	case len(valid) == 0:
+		return Candidate{}, clierr.New(clierr.NotFound, "no %s matches '%s'", kind, ref)
+	case len(valid) == 1 || opts.First:
+		return valid[0], nil
+	case ui.Interactive():
+		var preview func(id string) string
+		if kind != Library {
+			preview = func(id string) string { return previewItem(ctx, id) }
+		}
+		choice, err := pickFrom(fmt.Sprintf("Several %s match '%s'", kind.plural(), ref), valid, false, preview)
+		if err != nil {
+			return Candidate{}, err
+		}
+		for _, c := range valid {
+			if c.ID == choice[0] {
+				return c, nil
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "'%s' matches %d %s, use an ID or --first:", ref, len(valid), kind.plural())
+	for _, c := range valid {
+		fmt.Fprintf(&b, "\n  %s  %s", c.Label(), c.detail())
+	}
+	return Candidate{}, clierr.New(clierr.Usage, "%s", b.String())
+}
@@ -0,0 +1,145 @@
+// Package timerange parses the human friendly times and time ranges accepted by flags such as --since.
+//
+// A time is one of:
+//   - a duration back from now: 90m, 1h30m, 36h, 3d, 2w, 1mo, 1y or combinations such as 1w3d
+//   - a date or date and time: 2026-01-02, 2026-01-02 15:04, 2026-01-02T15:04 or RFC 3339
+//   - now, today or yesterday, the last two meaning the start of that day
+//
+// A range is two times separated by "..", either of which may be left out for an open range,
+// e.g. 2026-01-01..2026-02-01, 2w..1w or ..yesterday. The end of a range is exclusive.
+package timerange
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Range is a span of time, a zero From or To leaves that side open
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// Parse parses a time or a range relative to the current time. A single time is the start of an open range.
+func Parse(s string) (Range, error) {
+	return ParseAt(s, time.Now())
+}
+
+// ParseAt parses a time or a range relative to now
+func ParseAt(s string, now time.Time) (Range, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(s), "..")
+	if !isRange {
+		t, err := ParseTime(from, now)
+		return Range{From: t}, err
+	}
+	if from == "" && to == "" {
+		return Range{}, fmt.Errorf("invalid range '%s', at least one side is needed", s)
+	}
+
+	var r Range
+	var err error
+	if from != "" {
+		if r.From, err = ParseTime(from, now); err != nil {
+			return Range{}, err
+		}
+	}
+	if to != "" {
+		if r.To, err = ParseTime(to, now); err != nil {
+			return Range{}, err
+		}
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && !r.From.Before(r.To) {
+		return Range{}, fmt.Errorf("invalid range '%s', the start must be before the end", s)
+	}
+	return r, nil
+}
+
+// Layouts accepted for absolute times, in local time unless they carry a zone
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// durationPart matches one number and unit of a duration, mo must come before m
+var durationPart = regexp.MustCompile(`(\d+(?:\.\d+)?)(mo|ms|us|µs|ns|[smhdwy])`)
+
+// ParseTime parses a single time relative to now
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, ok := ago(s, now); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s', expected a date (2006-01-02), a duration such as 1h30m, 3d, 2w or 1mo, today or yesterday", s)
+}
+
+// ago subtracts a duration such as 1w3d from now. Days, weeks, months and years follow the calendar.
+func ago(s string, now time.Time) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), true
+	}
+
+	matches := durationPart.FindAllStringSubmatchIndex(s, -1)
+	t, pos := now, 0
+	for _, m := range matches {
+		if m[0] != pos {
+			return time.Time{}, false
+		}
+		pos = m[1]
+
+		value, unit := s[m[2]:m[3]], s[m[4]:m[5]]
+		switch unit {
+		case "d", "w", "mo", "y":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return time.Time{}, false
+			}
+			switch unit {
+			case "d":
+				t = t.AddDate(0, 0, -n)
+			case "w":
+				t = t.AddDate(0, 0, -7*n)
+			case "mo":
+				t = t.AddDate(0, -n, 0)
+			case "y":
+				t = t.AddDate(-n, 0, 0)
+			}
+		default:
+			d, err := time.ParseDuration(value + unit)
+			if err != nil {
+				return time.Time{}, false
+			}
+			t = t.Add(-d)
+		}
+	}
+	return t, len(matches) > 0 && pos == len(s)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,81 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAt(t *testing.T) {
+	now := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		in      string
+		want    Range
+		wantErr bool
+	}{
+		// Durations back from now
+		{in: "90m", want: Range{From: date(2026, 3, 15, 13, 0)}},
+		{in: "1h30m", want: Range{From: date(2026, 3, 15, 13, 0)}},
+		{in: "3d", want: Range{From: date(2026, 3, 12, 14, 30)}},
+		{in: "2w", want: Range{From: date(2026, 3, 1, 14, 30)}},
+		{in: "1mo", want: Range{From: date(2026, 2, 15, 14, 30)}},
+		{in: "1y", want: Range{From: date(2025, 3, 15, 14, 30)}},
+		{in: "1w3d", want: Range{From: date(2026, 3, 5, 14, 30)}},
+		{in: "1mo2d", want: Range{From: date(2026, 2, 13, 14, 30)}},
+		{in: "1d12h", want: Range{From: date(2026, 3, 14, 2, 30)}},
+		{in: "1y1mo1w1d", want: Range{From: date(2025, 2, 7, 14, 30)}},
+
+		// Absolute times
+		{in: "2026-01-02", want: Range{From: date(2026, 1, 2, 0, 0)}},
+		{in: "2026-01-02 15:04", want: Range{From: date(2026, 1, 2, 15, 4)}},
+		{in: "2026-01-02T15:04", want: Range{From: date(2026, 1, 2, 15, 4)}},
+		{in: "2026-01-02 15:04:05", want: Range{From: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)}},
+		{in: "2026-01-02T15:04:05Z", want: Range{From: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)}},
+
+		// Named times
+		{in: "now", want: Range{From: now}},
+		{in: "today", want: Range{From: date(2026, 3, 15, 0, 0)}},
+		{in: "Yesterday", want: Range{From: date(2026, 3, 14, 0, 0)}},
+
+		// Ranges
+		{in: "2026-01-01..2026-02-01", want: Range{From: date(2026, 1, 1, 0, 0), To: date(2026, 2, 1, 0, 0)}},
+		{in: "2w..1w", want: Range{From: date(2026, 3, 1, 14, 30), To: date(2026, 3, 8, 14, 30)}},
+		{in: "yesterday..today", want: Range{From: date(2026, 3, 14, 0, 0), To: date(2026, 3, 15, 0, 0)}},
+		{in: "..yesterday", want: Range{To: date(2026, 3, 14, 0, 0)}},
+		{in: "3d..", want: Range{From: date(2026, 3, 12, 14, 30)}},
+
+		// Invalid input
+		{in: "", wantErr: true},
+		{in: "..", wantErr: true},
+		{in: "soon", wantErr: true},
+		{in: "3x", wantErr: true},
+		{in: "1w 3d", wantErr: true},
+		{in: "1.5d", wantErr: true},
+		{in: "-3d", wantErr: true},
+		{in: "2026-13-01", wantErr: true},
+		{in: "1w..2w", wantErr: true},
+		{in: "today..today", wantErr: true},
+		{in: "3d..soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseAt(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseAt(%q) = %+v, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAt(%q) failed: %v", tt.in, err)
+			}
+			if !got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To) {
+				t.Errorf("ParseAt(%q) = %v..%v, want %v..%v", tt.in, got.From, got.To, tt.want.From, tt.want.To)
+			}
+		})
+	}
+}
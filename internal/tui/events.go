@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ygelfand/plexctl/internal/plex"
+	"github.com/ygelfand/plexctl/internal/ui"
+)
+
+// eventFlushDelay batches bursts of notifications, e.g. during a library scan, into one refresh
+const eventFlushDelay = time.Second
+
+type serverChangesMsg plex.Changes
+type flushChangesMsg struct{}
+
+// startEvents (re)subscribes to server notifications, e.g. after switching users
+func (c *Controller) startEvents() {
+	if c.stopEvents != nil {
+		c.stopEvents()
+	}
+	var ctx context.Context
+	ctx, c.stopEvents = context.WithCancel(context.Background())
+	go plex.WatchEvents(ctx, c.events)
+}
+
+// waitForEvent invalidates the cache for the next notification off the UI loop
+func (c *Controller) waitForEvent() tea.Cmd {
+	return func() tea.Msg {
+		e := <-c.events
+		return serverChangesMsg(plex.Invalidate(e))
+	}
+}
+
+func (c *Controller) handleServerChanges(changes plex.Changes) tea.Cmd {
+	cmds := []tea.Cmd{c.waitForEvent()}
+	if changes.Empty() {
+		return tea.Batch(cmds...)
+	}
+
+	c.pendingChanges.Merge(changes)
+	if !c.flushScheduled {
+		c.flushScheduled = true
+		cmds = append(cmds, tea.Tick(eventFlushDelay, func(time.Time) tea.Msg {
+			return flushChangesMsg{}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// flushChanges broadcasts the pending changes to every tab so inactive ones are up to date too
+func (c *Controller) flushChanges() tea.Cmd {
+	msg := ui.MediaChangedMsg{
+		Sections: c.pendingChanges.Sections,
+		Items:    c.pendingChanges.Items,
+		Sessions: c.pendingChanges.Sessions,
+	}
+	c.pendingChanges = plex.Changes{}
+	c.flushScheduled = false
+
+	var cmds []tea.Cmd
+	for i, model := range c.tabManager.tabModels {
+		if model != nil {
+			var cmd tea.Cmd
+			c.tabManager.tabModels[i], cmd = model.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
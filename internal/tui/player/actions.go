@@ -24,21 +24,14 @@ func PlayMedia(metadata *components.Metadata, noReport bool, tctMode bool, start
 		return func() tea.Msg { return fmt.Errorf("no active server") }
 	}
 
+	// The player authenticates with plex.StreamHeaders, so the URLs carry no token
 	part := metadata.Media[0].Part[0]
-	separator := "?"
-	if strings.Contains(part.Key, "?") {
-		separator = "&"
-	}
-	playURL := fmt.Sprintf("%s%s%sX-Plex-Token=%s", serverCfg.URL, part.Key, separator, cfg.Token)
+	playURL := serverCfg.URL + part.Key
 
 	var subtitles []ExternalSubtitle
 	for _, stream := range part.Stream {
 		if stream.StreamType == components.StreamTypeSubtitle && stream.Key != "" {
-			sep := "?"
-			if strings.Contains(stream.Key, "?") {
-				sep = "&"
-			}
-			subURL := fmt.Sprintf("%s%s%sX-Plex-Token=%s", serverCfg.URL, stream.Key, sep, cfg.Token)
+			subURL := serverCfg.URL + stream.Key
 			lang := ""
 			if stream.LanguageCode != nil {
 				lang = *stream.LanguageCode
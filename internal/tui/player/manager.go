@@ -143,6 +143,10 @@ func (pm *PlayerManager) Play(url, title, ratingKey string, noReport bool, tctMo
 		startSec := float64(startOffset) / 1000.0
 		slog.Debug("PlayerManager: sending loadfile to mpv", "url", url, "startSec", startSec)
 
+		// The token is sent as a header so it stays out of URLs, where mpv would show and log it
+		if _, err := pm.conn.Call("set_property", "http-header-fields", plex.StreamHeaders()); err != nil {
+			slog.Warn("PlayerManager: failed to set request headers", "error", err)
+		}
 		pm.conn.Call("loadfile", url, "replace", "-1", fmt.Sprintf("start=%.3f", startSec))
 		pm.conn.Call("set_property", "pause", false)
 		pm.conn.Call("set_property", "force-media-title", title)
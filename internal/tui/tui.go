@@ -38,6 +38,11 @@ type Controller struct {
 
 	playerStatus player.PlayerStatus
 	returnTabIdx int
+
+	events         chan plex.ServerEvent
+	stopEvents     context.CancelFunc
+	pendingChanges plex.Changes
+	flushScheduled bool
 }
 
 type switchUserSuccessMsg struct{}
@@ -77,6 +82,7 @@ func NewController(data plex.LoaderResult) *Controller {
 		navigator:    NewNavigator(activedTheme),
 		alert:        alert,
 		returnTabIdx: -1,
+		events:       make(chan plex.ServerEvent, 64),
 	}
 
 	c.tabManager = NewTabManager(data, activedTheme, ui.SidebarWidth)
@@ -104,6 +110,9 @@ func (c *Controller) Init() tea.Cmd {
 	cmds = append(cmds, c.player.PollUpdates())
 	cmds = append(cmds, c.player.Reconnect())
 	cmds = append(cmds, c.alert.Init())
+
+	c.startEvents()
+	cmds = append(cmds, c.waitForEvent())
 	return tea.Batch(cmds...)
 }
 
@@ -170,8 +179,16 @@ func (c *Controller) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ui.SwitchUserMsg:
 		return c, c.handleSwitchUser(msg)
 
+	case serverChangesMsg:
+		return c, c.handleServerChanges(plex.Changes(msg))
+
+	case flushChangesMsg:
+		return c, c.flushChanges()
+
 	case switchUserSuccessMsg:
 		c.navigator.Pop()
+		// The notification stream authenticates as the previous user
+		c.startEvents()
 		// Return a command that triggers a full data reload
 		return c, c.fullReload()
 
@@ -266,6 +283,8 @@ func (c *Controller) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return c, c.navigator.Push(tuisearch.NewSearchOverlayModel(c.theme))
 		case "u":
 			return c, c.triggerUserSwitch()
+		case "R":
+			return c, c.resumeLatest()
 		case "shift+tab":
 			return c, c.tabManager.PrevTab()
 		}
@@ -466,6 +485,20 @@ func (c *Controller) fullReload() tea.Cmd {
 	}
 }
 
+// resumeLatest plays the first continue watching item, asking where to start when it is in progress
+func (c *Controller) resumeLatest() tea.Cmd {
+	return func() tea.Msg {
+		items, err := plex.GetContinueWatching(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("nothing to continue watching")
+		}
+		return ui.RequestPlayMsg{RatingKey: ui.PtrToString(items[0].RatingKey)}
+	}
+}
+
 func (c *Controller) showHelp() tea.Cmd {
 	keys := []ui.HelpKey{
 		{Key: "tab", Desc: "Switch Library"},
@@ -474,6 +507,7 @@ func (c *Controller) showHelp() tea.Cmd {
 		{Key: "ctrl+l", Desc: "Libraries"},
 		{Key: "u", Desc: "Switch User"},
 		{Key: "p", Desc: "Play Selected"},
+		{Key: "R", Desc: "Resume Latest"},
 		{Key: "x", Desc: "Stop Playback"},
 		{Key: "q", Desc: "Quit"},
 		{Key: "?", Desc: "Help"},
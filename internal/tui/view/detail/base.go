@@ -37,6 +37,10 @@ func (b *DetailBase) SetSize(width, height int) {
 	b.UpdateLayout()
 }
 
+func (b *DetailBase) GetRatingKey() string {
+	return b.RatingKey
+}
+
 func (b *DetailBase) IsAtRoot() bool {
 	return true
 }
@@ -38,9 +38,15 @@ type BackMsg struct{}
 // Shared Item Type for Lists
 type metadataItem struct {
 	metadata components.Metadata
+	badge    string // short note shown after the title, e.g. missing episodes
 }
 
-func (i metadataItem) Title() string { return i.metadata.Title }
+func (i metadataItem) Title() string {
+	if i.badge != "" {
+		return i.metadata.Title + "  " + i.badge
+	}
+	return i.metadata.Title
+}
 func (i metadataItem) Description() string {
 	if i.metadata.Summary != nil {
 		return *i.metadata.Summary
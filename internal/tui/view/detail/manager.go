@@ -51,6 +51,24 @@ func (m *DetailManager) Update(msg tea.Msg) (tea.Cmd, bool) {
 	return cmd, true
 }
 
+// Refresh reloads the detail view if the changes touch the item it shows
+func (m *DetailManager) Refresh(changes ui.MediaChangedMsg, sectionID string) tea.Cmd {
+	if m.View == nil {
+		return nil
+	}
+	refresher, ok := m.View.(ui.Refreshable)
+	if !ok {
+		return nil
+	}
+	if rk, ok := m.View.(ui.RatingKeyProvider); ok && changes.HasItem(rk.GetRatingKey()) {
+		return refresher.Refresh()
+	}
+	if sectionID != "" && changes.HasSection(sectionID) {
+		return refresher.Refresh()
+	}
+	return nil
+}
+
 func (m *DetailManager) ViewContent() string {
 	if m.View == nil {
 		return ""
@@ -2,6 +2,8 @@ package detail
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/charmbracelet/bubbles/list"
@@ -15,6 +17,7 @@ import (
 type SeasonDetailView struct {
 	DetailBase
 	children        []components.Metadata
+	gaps            plex.SeasonGaps
 	episodeList     list.Model
 	selectedEpisode *EpisodeDetailView
 }
@@ -84,6 +87,9 @@ func (v *SeasonDetailView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case detailDataMsg:
 		v.children = msg.children
+		if msg.metadata != nil {
+			v.gaps = plex.FindSeasonGaps(*msg.metadata, msg.children)
+		}
 		var items []list.Item
 		for _, child := range v.children {
 			items = append(items, metadataItem{metadata: child})
@@ -154,6 +160,14 @@ func (v *SeasonDetailView) View() string {
 	if watched != "" {
 		headerInfo = lipgloss.NewStyle().Foreground(v.Theme.BrightCyan()).MarginBottom(1).Width(rightWidth).Render(watched)
 	}
+	if len(v.gaps.Missing) > 0 {
+		var missing []string
+		for _, e := range v.gaps.Missing {
+			missing = append(missing, fmt.Sprintf("E%02d", e))
+		}
+		headerInfo = lipgloss.JoinVertical(lipgloss.Left, headerInfo,
+			lipgloss.NewStyle().Foreground(v.Theme.BrightRed()).MarginBottom(1).Width(rightWidth).Render("Missing: "+strings.Join(missing, ", ")))
+	}
 
 	infoSection := lipgloss.JoinVertical(lipgloss.Left,
 		v.RenderHeader(rightWidth),
@@ -17,10 +17,16 @@ import (
 type ShowDetailView struct {
 	DetailBase
 	children       []components.Metadata
+	gaps           []plex.SeasonGaps
 	seasonList     list.Model
 	selectedSeason *SeasonDetailView
 }
 
+type showGapsMsg struct {
+	ratingKey string
+	gaps      []plex.SeasonGaps
+}
+
 func NewShowDetailView(ratingKey string, theme tint.Tint) *ShowDetailView {
 	return &ShowDetailView{
 		DetailBase: NewDetailBase(ratingKey, theme),
@@ -29,7 +35,33 @@ func NewShowDetailView(ratingKey string, theme tint.Tint) *ShowDetailView {
 }
 
 func (v *ShowDetailView) Init() tea.Cmd {
-	return v.fetchData
+	return tea.Batch(v.fetchData, v.fetchGaps)
+}
+
+// fetchGaps looks for missing episodes separately, as it has to load every season
+func (v *ShowDetailView) fetchGaps() tea.Msg {
+	gaps, err := plex.FindShowGaps(context.Background(), v.RatingKey, false)
+	if err != nil {
+		return nil
+	}
+	return showGapsMsg{ratingKey: v.RatingKey, gaps: gaps}
+}
+
+func (v *ShowDetailView) setSeasonItems() {
+	var items []list.Item
+	for _, child := range v.children {
+		item := metadataItem{metadata: child}
+		for _, g := range v.gaps {
+			if g.RatingKey != "" && g.RatingKey == ui.PtrToString(child.RatingKey) {
+				item.badge = fmt.Sprintf("(%d missing)", len(g.Missing))
+				if g.Absent {
+					item.badge = "(no episodes)"
+				}
+			}
+		}
+		items = append(items, item)
+	}
+	v.seasonList.SetItems(items)
 }
 
 func (v *ShowDetailView) Refresh() tea.Cmd {
@@ -37,7 +69,7 @@ func (v *ShowDetailView) Refresh() tea.Cmd {
 		return v.selectedSeason.Refresh()
 	}
 	v.Loading = true
-	return func() tea.Msg {
+	return tea.Batch(func() tea.Msg {
 		ctx := context.Background()
 		meta, err := plex.GetMetadata(ctx, v.RatingKey, true)
 		if err != nil {
@@ -45,7 +77,7 @@ func (v *ShowDetailView) Refresh() tea.Cmd {
 		}
 		children, _ := plex.GetChildren(ctx, v.RatingKey)
 		return detailDataMsg{metadata: meta, children: children}
-	}
+	}, v.fetchGaps)
 }
 
 func (v *ShowDetailView) GetSelectedMetadata() *components.Metadata {
@@ -86,11 +118,12 @@ func (v *ShowDetailView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case detailDataMsg:
 		v.children = msg.children
-		var items []list.Item
-		for _, child := range v.children {
-			items = append(items, metadataItem{metadata: child})
+		v.setSeasonItems()
+	case showGapsMsg:
+		if msg.ratingKey == v.RatingKey {
+			v.gaps = msg.gaps
+			v.setSeasonItems()
 		}
-		v.seasonList.SetItems(items)
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "enter":
@@ -160,6 +193,9 @@ func (v *ShowDetailView) View() string {
 		headerParts = append(headerParts, watched)
 	}
 	headerInfo := strings.Join(headerParts, "  •  ")
+	if missing := v.missingCount(); missing > 0 {
+		headerInfo += "  •  " + lipgloss.NewStyle().Foreground(v.Theme.BrightRed()).Render(fmt.Sprintf("%d missing", missing))
+	}
 
 	var genres []string
 	for _, g := range v.Metadata.Genre {
@@ -208,3 +244,12 @@ func (v *ShowDetailView) View() string {
 	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, mainLayout, listContent)) +
 		"\n\n " + lipgloss.NewStyle().Foreground(v.Theme.BrightBlack()).Render("[enter] Select Season | [esc] Back")
 }
+
+// missingCount returns the missing episodes of the show, counting absent seasons once
+func (v *ShowDetailView) missingCount() int {
+	total := 0
+	for _, g := range v.gaps {
+		total += g.MissingCount()
+	}
+	return total
+}
@@ -58,6 +58,14 @@ func (v *HomeView) fetchHubs() tea.Msg {
 }
 
 func (v *HomeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if changes, ok := msg.(ui.MediaChangedMsg); ok {
+		if len(changes.Items) == 0 && len(changes.Sections) == 0 {
+			return v, nil
+		}
+		// Refetch quietly so the hubs keep their state instead of flashing the loading screen
+		return v, tea.Batch(v.details.Refresh(changes, ""), v.fetchHubs)
+	}
+
 	if v.details.Active() {
 		cmd, handled := v.details.Update(msg)
 		if handled {
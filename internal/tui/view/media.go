@@ -6,7 +6,6 @@ import (
 	"log/slog"
 
 	"github.com/LukeHagar/plexgo/models/components"
-	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -100,12 +99,59 @@ func (v *MediaView) Refresh() tea.Cmd {
 			return refresher.Refresh()
 		}
 	}
+	return v.reload()
+}
+
+func (v *MediaView) reload() tea.Cmd {
 	v.allMetadata = nil
 	v.loadedItems = 0
 	v.isLoading = true
 	return v.fetchPage(0)
 }
 
+// applyChanges reloads the section or the changed items after server notifications
+func (v *MediaView) applyChanges(changes ui.MediaChangedMsg) tea.Cmd {
+	cmds := []tea.Cmd{v.details.Refresh(changes, v.sectionID)}
+
+	if v.loadedItems == 0 || v.isLoading {
+		return tea.Batch(cmds...)
+	}
+	if changes.HasSection(v.sectionID) {
+		return tea.Batch(append(cmds, v.reload())...)
+	}
+
+	var keys []string
+	for _, meta := range v.allMetadata {
+		if meta.RatingKey != nil && changes.HasItem(*meta.RatingKey) {
+			keys = append(keys, *meta.RatingKey)
+		}
+	}
+	if len(keys) > 0 {
+		cmds = append(cmds, v.fetchItems(keys))
+	}
+	return tea.Batch(cmds...)
+}
+
+type mediaItemsMsg struct {
+	sectionID string
+	items     []components.Metadata
+}
+
+func (v *MediaView) fetchItems(keys []string) tea.Cmd {
+	return func() tea.Msg {
+		var items []components.Metadata
+		for _, key := range keys {
+			meta, err := plex.GetMetadata(context.Background(), key, false)
+			if err != nil {
+				slog.Debug("MediaView: failed to refresh item", "key", key, "error", err)
+				continue
+			}
+			items = append(items, *meta)
+		}
+		return mediaItemsMsg{sectionID: v.sectionID, items: items}
+	}
+}
+
 func (v *MediaView) fetchPage(start int) tea.Cmd {
 	return func() tea.Msg {
 		slog.Debug("MediaView: fetchPage started", "section", v.sectionID, "start", start)
@@ -118,11 +164,7 @@ func (v *MediaView) fetchPage(start int) tea.Cmd {
 		serverID, _, _ := cfg.GetActiveServer()
 		cm, _ := cache.Get(cfg.CacheDir)
 
-		req := operations.ListContentRequest{
-			SectionID:           v.sectionID,
-			XPlexContainerStart: ui.Ptr(start),
-			XPlexContainerSize:  ui.Ptr(100),
-		}
+		req := plex.LibraryPageRequest(v.sectionID, start)
 
 		var body components.MediaContainerWithMetadata
 		err = cache.AutoCache(cm, serverID, req, plex.LibraryCacheTTL, &body, func() (*components.MediaContainerWithMetadata, error) {
@@ -158,6 +200,24 @@ func (v *MediaView) fetchPage(start int) tea.Cmd {
 }
 
 func (v *MediaView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ui.MediaChangedMsg:
+		return v, v.applyChanges(msg)
+	case mediaItemsMsg:
+		if msg.sectionID != v.sectionID {
+			return v, nil
+		}
+		for _, item := range msg.items {
+			for i, meta := range v.allMetadata {
+				if meta.RatingKey != nil && item.RatingKey != nil && *meta.RatingKey == *item.RatingKey {
+					v.allMetadata[i] = item
+				}
+			}
+		}
+		v.syncTableRows()
+		return v, v.posterGrid.SetItems(v.allMetadata)
+	}
+
 	if v.details.Active() {
 		// Parent-level updates still happen even if detail is active (like window size)
 		switch m := msg.(type) {
@@ -114,6 +114,11 @@ func (t *SessionsTab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		ui.UpdateTableTheme(&t.table, t.width, t.height)
 	case []table.Row:
 		t.table.SetRows(msg)
+	case ui.MediaChangedMsg:
+		if msg.Sessions {
+			return t, t.Refresh()
+		}
+		return t, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "s":
@@ -3,6 +3,7 @@ package view
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/LukeHagar/plexgo/models/operations"
 	"github.com/charmbracelet/bubbles/table"
@@ -13,10 +14,27 @@ import (
 	"github.com/ygelfand/plexctl/internal/ui"
 )
 
+// activitiesRefreshInterval is how often running activities are polled while the tab is open
+const activitiesRefreshInterval = 2 * time.Second
+
 type TasksTab struct {
 	table  table.Model
 	width  int
 	height int
+
+	activitiesTable table.Model
+	activities      []operations.Activity
+	focusActivities bool
+	pollGen         int
+}
+
+type activitiesMsg struct {
+	gen        int
+	activities []operations.Activity
+}
+
+type activitiesTickMsg struct {
+	gen int
 }
 
 func NewTasksTab(theme tint.Tint) *TasksTab {
@@ -27,17 +45,83 @@ func NewTasksTab(theme tint.Tint) *TasksTab {
 		{Title: "ENABLED", Width: 10},
 	}
 
+	activityColumns := []table.Column{
+		{Title: "ACTIVITY", Width: 30},
+		{Title: "DETAIL", Width: 25},
+		{Title: "PROGRESS", Width: 26},
+	}
+	activitiesTable := ui.NewTable(activityColumns, theme)
+	activitiesTable.Blur()
+
 	return &TasksTab{
-		table: ui.NewTable(columns, theme),
+		table:           ui.NewTable(columns, theme),
+		activitiesTable: activitiesTable,
 	}
 }
 
 func (t *TasksTab) Init() tea.Cmd {
-	return t.fetchTasks
+	// A new generation stops polling chains left over from earlier visits to the tab
+	t.pollGen++
+	return tea.Batch(t.fetchTasks, t.fetchActivities(t.pollGen))
 }
 
 func (t *TasksTab) Refresh() tea.Cmd {
-	return t.fetchTasks
+	return tea.Batch(t.fetchTasks, t.fetchActivities(t.pollGen))
+}
+
+func (t *TasksTab) fetchActivities(gen int) tea.Cmd {
+	return func() tea.Msg {
+		client, err := plex.NewClient()
+		if err != nil {
+			return err
+		}
+		activities, err := plex.ListActivities(context.Background(), client)
+		if err != nil {
+			return err
+		}
+		return activitiesMsg{gen: gen, activities: activities}
+	}
+}
+
+func (t *TasksTab) setActivities(activities []operations.Activity) {
+	t.activities = activities
+	rows := make([]table.Row, 0, len(activities))
+	for _, a := range activities {
+		p, known := plex.ActivityProgress(a)
+		rows = append(rows, table.Row{
+			ui.PtrToString(a.Title),
+			ui.PtrToString(a.Subtitle),
+			ui.ProgressBar(p, known, 20),
+		})
+	}
+	t.activitiesTable.SetRows(rows)
+}
+
+func (t *TasksTab) cancelActivity(id string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := plex.NewClient()
+		if err != nil {
+			return err
+		}
+		_, err = client.SDK.Activities.CancelActivity(context.Background(), operations.CancelActivityRequest{
+			ActivityID: id,
+		})
+		if err != nil {
+			return err
+		}
+		return t.fetchActivities(t.pollGen)()
+	}
+}
+
+// layoutTables splits the available height between the butler tasks and the activities panel
+func (t *TasksTab) layoutTables() {
+	total := ui.GetTableHeight(t.height)
+	tasksHeight := max(total/2, 3)
+	t.table.SetHeight(tasksHeight)
+	t.table.SetWidth(t.width)
+	// Two lines for the activities heading
+	t.activitiesTable.SetHeight(max(total-tasksHeight-2, 3))
+	t.activitiesTable.SetWidth(t.width)
 }
 
 func (t *TasksTab) fetchTasks() tea.Msg {
@@ -94,30 +178,68 @@ func (t *TasksTab) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		t.width = msg.Width
 		t.height = msg.Height
-		t.table.SetHeight(ui.GetTableHeight(t.height))
-		t.table.SetWidth(t.width)
+		t.layoutTables()
 	case ui.ThemeChangedMsg:
 		ui.UpdateTableTheme(&t.table, t.width, t.height)
+		ui.UpdateTableTheme(&t.activitiesTable, t.width, t.height)
+		t.layoutTables()
+		if t.focusActivities {
+			t.table.Blur()
+		} else {
+			t.activitiesTable.Blur()
+		}
 	case []table.Row:
 		t.table.SetRows(msg)
+	case activitiesMsg:
+		if msg.gen != t.pollGen {
+			return t, nil
+		}
+		t.setActivities(msg.activities)
+		return t, tea.Tick(activitiesRefreshInterval, func(time.Time) tea.Msg {
+			return activitiesTickMsg{gen: msg.gen}
+		})
+	case activitiesTickMsg:
+		if msg.gen != t.pollGen {
+			return t, nil
+		}
+		return t, t.fetchActivities(msg.gen)
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "a": // Switch between tasks and activities
+			t.focusActivities = !t.focusActivities
+			if t.focusActivities {
+				t.table.Blur()
+				t.activitiesTable.Focus()
+			} else {
+				t.activitiesTable.Blur()
+				t.table.Focus()
+			}
+			return t, nil
 		case "s": // Start task
 			selected := t.table.SelectedRow()
-			if len(selected) > 0 {
+			if !t.focusActivities && len(selected) > 0 {
 				return t, t.startTask(selected[0])
 			}
 		case "x": // Stop task
 			selected := t.table.SelectedRow()
-			if len(selected) > 0 {
+			if !t.focusActivities && len(selected) > 0 {
 				return t, t.stopTask(selected[0])
 			}
+		case "c": // Cancel activity
+			idx := t.activitiesTable.Cursor()
+			if t.focusActivities && idx >= 0 && idx < len(t.activities) && ui.PtrToBool(t.activities[idx].Cancellable) {
+				return t, t.cancelActivity(ui.PtrToString(t.activities[idx].UUID))
+			}
 		case "r":
 			return t, t.Refresh()
 		}
 	}
 
-	t.table, cmd = t.table.Update(msg)
+	if t.focusActivities {
+		t.activitiesTable, cmd = t.activitiesTable.Update(msg)
+	} else {
+		t.table, cmd = t.table.Update(msg)
+	}
 	return t, cmd
 }
 
@@ -154,19 +276,29 @@ func (t *TasksTab) stopTask(name string) tea.Cmd {
 }
 
 func (t *TasksTab) View() string {
+	theme := ui.GetLayout().Theme()
+	muted := lipgloss.NewStyle().Padding(0, 2).Foreground(theme.BrightBlack())
+
+	tasks := t.table.View()
 	if len(t.table.Rows()) == 0 {
-		return lipgloss.NewStyle().
-			Padding(2).
-			Foreground(ui.GetLayout().Theme().BrightBlack()).
-			Render("No butler tasks found.")
+		tasks = muted.Render("No butler tasks found.")
 	}
-	return t.table.View()
+
+	heading := ui.AccentStyle(theme).Bold(true).MarginTop(1).Render("ACTIVITIES")
+	activities := t.activitiesTable.View()
+	if len(t.activities) == 0 {
+		activities = muted.Render("No activities running.")
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, tasks, heading, activities)
 }
 
 func (t *TasksTab) HelpKeys() []ui.HelpKey {
 	return []ui.HelpKey{
 		{Key: "s", Desc: "Start Task"},
 		{Key: "x", Desc: "Stop Task"},
+		{Key: "a", Desc: "Focus Tasks/Activities"},
+		{Key: "c", Desc: "Cancel Activity"},
 		{Key: "j/up", Desc: "Move Up"},
 		{Key: "k/down", Desc: "Move Down"},
 	}
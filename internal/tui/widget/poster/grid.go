@@ -50,6 +50,7 @@ func (m *PosterGrid) SetItems(metadata []components.Metadata) tea.Cmd {
 	for i, meta := range metadata {
 		if i < len(m.Items) && m.Items[i].Metadata.RatingKey != nil && meta.RatingKey != nil && *m.Items[i].Metadata.RatingKey == *meta.RatingKey {
 			newItems[i] = m.Items[i]
+			newItems[i].Metadata = meta
 		} else {
 			newItems[i] = &PosterItem{Metadata: meta, Loading: true}
 			rk := ""
@@ -38,51 +38,61 @@ type ItemSelectedMsg struct {
 // FetchPoster returns a command to fetch and render a poster
 func FetchPoster(listID int, index int, metadata components.Metadata) tea.Cmd {
 	return func() tea.Msg {
-		rk := ""
-		if metadata.RatingKey != nil {
-			rk = *metadata.RatingKey
+		view, err := Render(context.Background(), metadata, ui.PosterWidth)
+		if err != nil || view == "" {
+			return nil
 		}
+		return PosterLoadedMsg{ListID: listID, Index: index, View: view}
+	}
+}
 
-		if rk != "" {
-			if cached, ok := plex.GetCachedPoster(rk, ui.PosterWidth); ok {
-				return PosterLoadedMsg{ListID: listID, Index: index, View: cached}
-			}
-		}
+// Render draws the poster of an item width cells wide, using the show's poster for episodes and seasons.
+// It returns an empty string for items without a poster.
+func Render(ctx context.Context, metadata components.Metadata, width int) (string, error) {
+	rk := ""
+	if metadata.RatingKey != nil {
+		rk = *metadata.RatingKey
+	}
 
-		path := ""
-		if metadata.GrandparentThumb != nil {
-			path = *metadata.GrandparentThumb
-		} else if metadata.ParentThumb != nil {
-			path = *metadata.ParentThumb
-		} else if metadata.Thumb != nil {
-			path = *metadata.Thumb
+	if rk != "" {
+		if cached, ok := plex.GetCachedPoster(rk, width); ok {
+			return cached, nil
 		}
+	}
 
-		if path == "" {
-			return nil
-		}
+	path := ""
+	if metadata.GrandparentThumb != nil {
+		path = *metadata.GrandparentThumb
+	} else if metadata.ParentThumb != nil {
+		path = *metadata.ParentThumb
+	} else if metadata.Thumb != nil {
+		path = *metadata.Thumb
+	}
 
-		data, err := plex.GetImage(context.Background(), path)
-		if err != nil {
-			return nil
-		}
+	if path == "" {
+		return "", nil
+	}
 
-		img, _, err := image.Decode(bytes.NewReader(data))
-		if err != nil {
-			return nil
-		}
+	data, err := plex.GetImage(ctx, path)
+	if err != nil {
+		return "", err
+	}
 
-		imgStr, err := gopixels.FromImageStream(img, ui.PosterWidth, 0, "halfcell", true)
-		if err != nil {
-			return nil
-		}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
 
-		if rk != "" {
-			plex.SetCachedPoster(rk, ui.PosterWidth, imgStr)
-		}
+	imgStr, err := gopixels.FromImageStream(img, width, 0, "halfcell", true)
+	if err != nil {
+		return "", err
+	}
 
-		return PosterLoadedMsg{ListID: listID, Index: index, View: imgStr}
+	if rk != "" {
+		plex.SetCachedPoster(rk, width, imgStr)
 	}
+
+	return imgStr, nil
 }
 
 // RenderPosterItem renders a single poster with its labels
@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ygelfand/plexctl/internal/config"
+)
+
+// Format is an output format selected with -o
+type Format struct {
+	Name    string
+	Aliases []string
+
+	// Arg names the argument of formats that take one, e.g. PATH for -o jsonpath=PATH
+	Arg string
+
+	// Titled formats print the title above the data, the others are meant for scripts
+	Titled bool
+
+	// JSONErrors formats report errors as json objects on stderr, for scripts reading the output
+	JSONErrors bool
+
+	// Raw formats print the raw data rather than the rows
+	Raw bool
+
+	// Print prints all of the data at once
+	Print func(d OutputData, arg string) error
+
+	// Validate checks the argument before any request is made, optional
+	Validate func(arg string) error
+
+	// Stream starts printing a listing page by page given its first page, optional for formats
+	// that need all of the data at once
+	Stream func(d OutputData, arg string, first [][]string) (PageWriter, error)
+}
+
+// PageWriter prints the pages of a listing as they are fetched
+type PageWriter interface {
+	// WritePage prints the rows of one page, and the raw items, one per row, for formats that use them
+	WritePage(rows [][]string, raw []any) error
+
+	// Close finishes the output, e.g. closing a table or a json array
+	Close() error
+}
+
+var formats []Format
+
+// RegisterFormat adds an output format, replacing any format of the same name
+func RegisterFormat(f Format) {
+	for i, existing := range formats {
+		if existing.Name == f.Name {
+			formats[i] = f
+			return
+		}
+	}
+	formats = append(formats, f)
+}
+
+// LookupFormat finds a format by name or alias
+func LookupFormat(name string) (Format, bool) {
+	for _, f := range formats {
+		if f.Name == name {
+			return f, true
+		}
+		for _, alias := range f.Aliases {
+			if alias == name {
+				return f, true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// OutputFormats lists the registered formats, those taking an argument as e.g. jsonpath=PATH
+func OutputFormats() []string {
+	var names []string
+	for _, f := range formats {
+		if f.Arg != "" {
+			names = append(names, f.Name+"="+f.Arg)
+		} else {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func init() {
+	RegisterFormat(Format{Name: "table", Titled: true, Print: withoutArg(OutputData.printTable), Stream: newTableStream})
+	RegisterFormat(Format{Name: "json", JSONErrors: true, Raw: true, Print: withoutArg(OutputData.printJSON), Stream: newJSONStream})
+	RegisterFormat(Format{Name: "ndjson", JSONErrors: true, Raw: true, Print: withoutArg(OutputData.printNDJSON), Stream: newNDJSONStream})
+	RegisterFormat(Format{Name: "json-pretty", JSONErrors: true, Raw: true, Print: withoutArg(OutputData.printJSONPretty)})
+	RegisterFormat(Format{Name: "yaml", Raw: true, Print: withoutArg(OutputData.printYAML)})
+	RegisterFormat(Format{Name: "csv", Print: printPages(newCSVStream), Stream: newCSVStream})
+	RegisterFormat(Format{Name: "tsv", Print: printPages(newTSVStream), Stream: newTSVStream})
+	RegisterFormat(Format{Name: "txt", Aliases: []string{"text"}, Titled: true, Print: printPages(newTextStream), Stream: newTextStream})
+	RegisterFormat(Format{Name: "markdown", Aliases: []string{"md"}, Print: printPages(newMarkdownStream), Stream: newMarkdownStream})
+	RegisterFormat(Format{Name: "html", Print: printPages(newHTMLStream), Stream: newHTMLStream})
+	RegisterFormat(Format{
+		Name:     "go-template",
+		Arg:      "TEMPLATE",
+		Raw:      true,
+		Print:    OutputData.printGoTemplate,
+		Validate: func(arg string) error { _, err := parseGoTemplate(arg); return err },
+	})
+	RegisterFormat(Format{
+		Name:     "jsonpath",
+		Arg:      "PATH",
+		Raw:      true,
+		Print:    OutputData.printJSONPath,
+		Validate: func(arg string) error { _, err := parseJSONPath(arg); return err },
+	})
+	RegisterFormat(Format{
+		Name:     "custom-columns",
+		Arg:      "HEADER:PATH,...",
+		Raw:      true,
+		Print:    OutputData.printCustomColumns,
+		Validate: func(arg string) error { _, err := parseCustomColumns(arg); return err },
+	})
+}
+
+// ParseOutputFormat splits a format such as jsonpath=.title into its lowercased name and argument
+func ParseOutputFormat(format string) (string, string) {
+	format = strings.Trim(format, "\"")
+	name, arg, _ := strings.Cut(format, "=")
+	return strings.ToLower(name), arg
+}
+
+// ValidateOutputFormat checks the format name and that templates, paths and columns parse
+func ValidateOutputFormat(format string) error {
+	name, arg := ParseOutputFormat(format)
+	f, ok := LookupFormat(name)
+	if !ok {
+		return fmt.Errorf("invalid output format: %s (allowed: %s)", format, strings.Join(OutputFormats(), ", "))
+	}
+	if f.Validate != nil {
+		return f.Validate(arg)
+	}
+	return nil
+}
+
+// TitledFormat reports whether the format prints titles, an empty format is the default table
+func TitledFormat(format string) bool {
+	name, _ := ParseOutputFormat(format)
+	if name == "" {
+		return true
+	}
+	f, ok := LookupFormat(name)
+	return ok && f.Titled
+}
+
+// JSONErrors reports whether errors are printed as json objects for the configured format
+func JSONErrors() bool {
+	f, _ := configuredFormat()
+	return f.JSONErrors
+}
+
+// RawFormat reports whether the configured format prints the raw data rather than the rows, and its name
+func RawFormat() (bool, string) {
+	f, _ := configuredFormat()
+	return f.Raw, f.Name
+}
+
+// configuredFormat returns the format from the config, falling back to table
+func configuredFormat() (Format, string) {
+	// Robustly handle potentially quoted format strings from config
+	name, arg := ParseOutputFormat(config.Get().OutputFormat)
+	if f, ok := LookupFormat(name); ok {
+		return f, arg
+	}
+	f, _ := LookupFormat("table")
+	return f, arg
+}
+
+// Print handles the output based on the configured format
+func (d OutputData) Print() error {
+	f, arg := configuredFormat()
+	return f.Print(d, arg)
+}
+
+func withoutArg(print func(OutputData) error) func(OutputData, string) error {
+	return func(d OutputData, _ string) error {
+		return print(d)
+	}
+}
+
+// printPages prints all of the data at once through a format's page writer
+func printPages(stream func(OutputData, string, [][]string) (PageWriter, error)) func(OutputData, string) error {
+	return func(d OutputData, arg string) error {
+		w, err := stream(d, arg, d.Rows)
+		if err != nil {
+			return err
+		}
+		if err := w.WritePage(d.Rows, RawItems(d.Raw, len(d.Rows))); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+}
+
+// RawList returns the elements of raw when it is a list, raw itself otherwise and nothing for nil
+func RawList(raw any) []any {
+	v := reflect.ValueOf(raw)
+	switch {
+	case !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()):
+		return nil
+	case v.Kind() != reflect.Slice:
+		return []any{raw}
+	}
+	return RawItems(raw, v.Len())
+}
+
+// RawItems returns the elements of raw when it is a list with one element per row, nil otherwise
+func RawItems(raw any, rows int) []any {
+	v := reflect.ValueOf(raw)
+	if v.Kind() != reflect.Slice || v.Len() != rows {
+		return nil
+	}
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items
+}
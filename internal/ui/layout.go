@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	tint "github.com/lrstanley/bubbletint"
@@ -155,3 +156,58 @@ func FormatDuration(ms int) string {
 	}
 	return fmt.Sprintf("%d:%02d", minutes, seconds%60)
 }
+
+// FormatBytes converts a byte count to a human-readable string such as 12.3 MB
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ProgressBar renders a plain text progress bar followed by the percentage, or a
+// placeholder when the progress is unknown. It has no styling so it can be used in tables.
+func ProgressBar(fraction float64, known bool, width int) string {
+	if !known {
+		return strings.Repeat("░", width) + "    ?"
+	}
+	fraction = min(max(fraction, 0), 1)
+	filled := int(float64(width) * fraction)
+	return fmt.Sprintf("%s%s %3.0f%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), fraction*100)
+}
+
+// Bar renders a plain text bar filled to fraction of width, using partial blocks for the remainder
+func Bar(fraction float64, width int) string {
+	eighths := int(min(max(fraction, 0), 1) * float64(width*8))
+	bar := strings.Repeat("█", eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		bar += string([]rune("▏▎▍▌▋▊▉")[rest-1])
+	}
+	return bar
+}
+
+// LiveLines redraws a block of terminal lines in place, used for CLI progress output
+type LiveLines struct {
+	count int
+}
+
+// Render replaces the previously rendered lines with lines
+func (l *LiveLines) Render(lines []string) {
+	if l.count > 0 {
+		fmt.Printf("\033[%dA", l.count)
+	}
+	for _, line := range lines {
+		fmt.Printf("\r\033[K%s\n", line)
+	}
+	if extra := l.count - len(lines); extra > 0 {
+		fmt.Print(strings.Repeat("\r\033[K\n", extra))
+		fmt.Printf("\033[%dA", extra)
+	}
+	l.count = len(lines)
+}
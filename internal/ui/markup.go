@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// markdownStream prints a GitHub flavored markdown table
+type markdownStream struct{}
+
+var markdownReplacer = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func newMarkdownStream(d OutputData, _ string, _ [][]string) (PageWriter, error) {
+	m := &markdownStream{}
+	separator := make([]string, len(d.Headers))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	return m, m.WritePage([][]string{d.Headers, separator}, nil)
+}
+
+func (m *markdownStream) WritePage(rows [][]string, _ []any) error {
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString("|")
+		for _, val := range row {
+			b.WriteString(" " + markdownReplacer.Replace(val) + " |")
+		}
+		b.WriteString("\n")
+	}
+	_, err := fmt.Print(b.String())
+	return err
+}
+
+func (m *markdownStream) Close() error {
+	return nil
+}
+
+// htmlStream prints an html table, for pasting into pages and emails
+type htmlStream struct{}
+
+func newHTMLStream(d OutputData, _ string, _ [][]string) (PageWriter, error) {
+	var b strings.Builder
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, h := range d.Headers {
+		b.WriteString("<th>" + html.EscapeString(h) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	_, err := fmt.Print(b.String())
+	return &htmlStream{}, err
+}
+
+func (h *htmlStream) WritePage(rows [][]string, _ []any) error {
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for _, val := range row {
+			b.WriteString("<td>" + html.EscapeString(val) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	_, err := fmt.Print(b.String())
+	return err
+}
+
+func (h *htmlStream) Close() error {
+	_, err := fmt.Println("</tbody>\n</table>")
+	return err
+}
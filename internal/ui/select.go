@@ -1,15 +1,52 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ygelfand/plexctl/internal/clierr"
+	"golang.org/x/term"
 )
 
+// ErrNoTerminal is returned by pickers when there is no terminal to ask the user on
+var ErrNoTerminal = clierr.Wrap(clierr.Usage, errors.New("no terminal to pick from, pass it as an argument"))
+
+const (
+	pickerHeight        = 10
+	pickerPreviewHeight = 24
+	pickerMinListWidth  = 40
+)
+
+// PickerItem is one choice offered by a Picker
+type PickerItem struct {
+	Title, Desc, Value string
+}
+
+// Picker asks the user to choose from a list that can be narrowed down by typing
+type Picker struct {
+	Title string
+	Items []PickerItem
+
+	// Multi lets several items be marked with space before confirming with enter
+	Multi bool
+
+	// Preview renders details of the highlighted item next to the list, optional. It runs in the
+	// background, so it may fetch what it shows.
+	Preview func(value string) string
+}
+
+// Interactive reports whether the user can be asked to choose, stdin and stdout both being terminals
+func Interactive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
 type item struct {
 	title, desc string
 	value       string
@@ -19,7 +56,10 @@ func (i item) Title() string       { return i.title }
 func (i item) Description() string { return i.desc }
 func (i item) FilterValue() string { return i.title + " " + i.desc }
 
-type itemDelegate struct{}
+type itemDelegate struct {
+	multi  bool
+	marked map[string]bool
+}
 
 func (d itemDelegate) Height() int                               { return 1 }
 func (d itemDelegate) Spacing() int                              { return 0 }
@@ -34,7 +74,17 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	itemStyle := lipgloss.NewStyle().PaddingLeft(4)
 	selectedItemStyle := lipgloss.NewStyle().PaddingLeft(2).Foreground(Accent(theme))
 
-	str := fmt.Sprintf("%d. %s (%s)", index+1, i.title, i.desc)
+	str := fmt.Sprintf("%d. %s", index+1, i.title)
+	if i.desc != "" {
+		str += fmt.Sprintf(" (%s)", i.desc)
+	}
+	if d.multi {
+		box := "[ ] "
+		if d.marked[i.value] {
+			box = "[x] "
+		}
+		str = box + str
+	}
 
 	fn := itemStyle.Render
 	if index == m.Index() {
@@ -43,37 +93,104 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		}
 	}
 
-	fmt.Fprint(w, fn(str))
+	fmt.Fprint(w, fn(Ellipsis(str, m.Width()-4)))
+}
+
+// previewMsg carries the rendered preview of an item
+type previewMsg struct {
+	value string
+	view  string
 }
 
 type model struct {
 	list     list.Model
-	choice   string
+	multi    bool
+	marked   map[string]bool
+	choice   []string
 	quitting bool
+
+	preview  func(value string) string
+	previews map[string]string
+	loading  string
+	width    int
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return m.loadPreview()
+}
+
+// loadPreview renders the preview of the highlighted item in the background, once per item
+func (m *model) loadPreview() tea.Cmd {
+	if m.preview == nil {
+		return nil
+	}
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return nil
+	}
+	if _, done := m.previews[i.value]; done || m.loading == i.value {
+		return nil
+	}
+	m.loading = i.value
+	preview := m.preview
+	return func() tea.Msg {
+		return previewMsg{value: i.value, view: preview(i.value)}
+	}
+}
+
+// layout sizes the list, leaving room for the preview next to it
+func (m *model) layout() {
+	width := m.width
+	if m.preview != nil {
+		width = max(m.width/2, pickerMinListWidth)
+	}
+	m.list.SetWidth(width)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
-		m.list.SetWidth(msg.Width)
+		m.width = msg.Width
+		m.layout()
+		height := pickerHeight
+		if m.preview != nil {
+			height = pickerPreviewHeight
+		}
+		m.list.SetHeight(max(min(height, msg.Height-2), 5))
 		return m, nil
 
+	case previewMsg:
+		m.previews[msg.value] = msg.view
+		if m.loading == msg.value {
+			m.loading = ""
+		}
+		return m, m.loadPreview()
+
 	case tea.KeyMsg:
+		// While typing a filter every key goes to the filter
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
 		switch keypress := msg.String(); keypress {
 		case "ctrl+c", "q":
 			m.quitting = true
 			return m, tea.Quit
 
-		case "enter":
-			i, ok := m.list.SelectedItem().(item)
-			if ok {
-				m.choice = i.value
+		case "esc":
+			if m.list.FilterState() == list.Unfiltered {
+				m.quitting = true
+				return m, tea.Quit
+			}
+
+		case " ":
+			if i, ok := m.list.SelectedItem().(item); ok && m.multi {
+				m.marked[i.value] = !m.marked[i.value]
+				return m, nil
 			}
+
+		case "enter":
+			m.choice = m.chosen()
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -81,46 +198,104 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadPreview())
+}
+
+// chosen returns the marked items in list order, or the highlighted one when none are marked
+func (m model) chosen() []string {
+	var values []string
+	for _, li := range m.list.Items() {
+		if i := li.(item); m.marked[i.value] {
+			values = append(values, i.value)
+		}
+	}
+	if len(values) > 0 {
+		return values
+	}
+	if i, ok := m.list.SelectedItem().(item); ok {
+		return []string{i.value}
+	}
+	return nil
 }
 
 func (m model) View() string {
-	if m.choice != "" || m.quitting {
+	if m.choice != nil || m.quitting {
 		return ""
 	}
-	return "\n" + m.list.View()
+	if m.preview == nil {
+		return "\n" + m.list.View()
+	}
+
+	view := "Loading..."
+	if i, ok := m.list.SelectedItem().(item); ok {
+		if p, done := m.previews[i.value]; done {
+			view = p
+		}
+	}
+	pane := lipgloss.NewStyle().
+		PaddingLeft(2).
+		Width(max(m.width-m.list.Width()-1, 0)).
+		MaxHeight(m.list.Height()).
+		Render(view)
+	return "\n" + lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), pane)
 }
 
-// SelectOption presents a list of options to the user and returns the selected value
-func SelectOption(title string, options []struct{ Title, Desc, Value string }) (string, error) {
-	if len(options) == 0 {
-		return "", fmt.Errorf("no options provided")
+// Run shows the picker and returns the values chosen, in list order. It fails with ErrNoTerminal
+// when stdin or stdout is not a terminal.
+func (p Picker) Run() ([]string, error) {
+	if len(p.Items) == 0 {
+		return nil, fmt.Errorf("no options provided")
+	}
+	if !Interactive() {
+		return nil, ErrNoTerminal
 	}
 
 	var items []list.Item
-	for _, o := range options {
+	for _, o := range p.Items {
 		items = append(items, item{title: o.Title, desc: o.Desc, value: o.Value})
 	}
 
+	marked := make(map[string]bool)
 	theme := CurrentTheme()
-	l := list.New(items, itemDelegate{}, 60, 10)
-	l.Title = title
+	l := list.New(items, itemDelegate{multi: p.Multi, marked: marked}, 60, pickerHeight)
+	l.Title = p.Title
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
 	l.Styles.Title = TitleStyle(theme)
+	if p.Multi {
+		mark := key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark"))
+		l.AdditionalShortHelpKeys = func() []key.Binding { return []key.Binding{mark} }
+		l.AdditionalFullHelpKeys = l.AdditionalShortHelpKeys
+	}
 
-	m := model{list: l}
+	m := model{
+		list:     l,
+		multi:    p.Multi,
+		marked:   marked,
+		preview:  p.Preview,
+		previews: make(map[string]string),
+	}
 
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
+	finalModel, err := tea.NewProgram(m).Run()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	res := finalModel.(model).choice
-	if res == "" {
-		return "", fmt.Errorf("no selection made")
+	if len(res) == 0 {
+		return nil, clierr.New(clierr.Cancelled, "no selection made")
 	}
-
 	return res, nil
 }
+
+// SelectOption presents a list of options to the user and returns the selected value
+func SelectOption(title string, options []struct{ Title, Desc, Value string }) (string, error) {
+	items := make([]PickerItem, len(options))
+	for i, o := range options {
+		items[i] = PickerItem(o)
+	}
+	res, err := Picker{Title: title, Items: items}.Run()
+	if err != nil {
+		return "", err
+	}
+	return res[0], nil
+}
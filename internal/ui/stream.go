@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lovelydeng/gomoji"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// streamWidthSlack is the room given to each streamed table column past its widest value on the first
+// page, including the cell padding
+const streamWidthSlack = 4
+
+// StreamWriter prints a listing a page at a time in the configured output format, so that large
+// listings show up as they are fetched
+type StreamWriter struct {
+	data   OutputData
+	format Format
+	arg    string
+	w      PageWriter
+}
+
+// NewStreamWriter returns a writer for the configured output format, or false when the format needs
+// all of the data at once (yaml, json-pretty, templates, paths and custom columns)
+func NewStreamWriter(title string, headers []string) (*StreamWriter, bool) {
+	f, arg := configuredFormat()
+	if f.Stream == nil {
+		return nil, false
+	}
+	return &StreamWriter{
+		data:   OutputData{Title: title, Headers: headers},
+		format: f,
+		arg:    arg,
+	}, true
+}
+
+// WritePage prints the rows of one page, or its raw items for json and ndjson. Nothing is printed
+// until the first non empty page, so an empty listing prints nothing but an empty json array.
+func (s *StreamWriter) WritePage(rows [][]string, raw []any) error {
+	if len(rows) == 0 && len(raw) == 0 {
+		return nil
+	}
+	if s.w == nil {
+		w, err := s.format.Stream(s.data, s.arg, rows)
+		if err != nil {
+			return err
+		}
+		s.w = w
+	}
+	return s.w.WritePage(rows, raw)
+}
+
+// Close finishes the output, closing the json array or the table
+func (s *StreamWriter) Close() error {
+	if s.w == nil {
+		// An empty listing is still an array for json consumers
+		if s.format.Name == "json" {
+			fmt.Println("[]")
+		}
+		return nil
+	}
+	return s.w.Close()
+}
+
+type tableStream struct {
+	table *tablewriter.Table
+}
+
+// newTableStream prints the title and headers. Columns are sized from the first page with a little room
+// for values such as IDs to grow, longer values on later pages are wrapped.
+func newTableStream(d OutputData, _ string, first [][]string) (PageWriter, error) {
+	if d.Title != "" {
+		fmt.Println(TitleStyle(CurrentTheme()).Render(d.Title))
+	}
+	widths := tw.NewMapper[int, int]()
+	for _, row := range append([][]string{d.Headers}, first...) {
+		for i, val := range sanitizeRow(row) {
+			widths.Set(i, max(widths.Get(i), twwidth.Width(val)+streamWidthSlack))
+		}
+	}
+	table := tablewriter.NewTable(os.Stdout,
+		tablewriter.WithStreaming(tw.StreamConfig{Enable: true}),
+		tablewriter.WithColumnWidths(widths),
+		tablewriter.WithRowAutoWrap(tw.WrapBreak),
+	)
+	if err := table.Start(); err != nil {
+		return nil, err
+	}
+	table.Header(d.Headers)
+	return &tableStream{table: table}, nil
+}
+
+func (t *tableStream) WritePage(rows [][]string, _ []any) error {
+	for _, row := range rows {
+		if err := t.table.Append(sanitizeRow(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tableStream) Close() error {
+	return t.table.Close()
+}
+
+// jsonStream prints the raw items as a json array, or one per line for ndjson
+type jsonStream struct {
+	lines bool
+	items int
+}
+
+func newJSONStream(OutputData, string, [][]string) (PageWriter, error) {
+	fmt.Print("[")
+	return &jsonStream{}, nil
+}
+
+func newNDJSONStream(OutputData, string, [][]string) (PageWriter, error) {
+	return &jsonStream{lines: true}, nil
+}
+
+func (j *jsonStream) WritePage(_ [][]string, raw []any) error {
+	for _, item := range raw {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		switch {
+		case j.lines:
+			fmt.Println(string(b))
+		case j.items > 0:
+			fmt.Print("," + string(b))
+		default:
+			fmt.Print(string(b))
+		}
+		j.items++
+	}
+	return nil
+}
+
+func (j *jsonStream) Close() error {
+	if !j.lines {
+		fmt.Println("]")
+	}
+	return nil
+}
+
+type csvStream struct {
+	w *csv.Writer
+}
+
+func newCSVStream(d OutputData, _ string, _ [][]string) (PageWriter, error) {
+	w := csv.NewWriter(os.Stdout)
+	return &csvStream{w: w}, w.Write(d.Headers)
+}
+
+func (c *csvStream) WritePage(rows [][]string, _ []any) error {
+	// WriteAll flushes, so every page is written out as it arrives
+	return c.w.WriteAll(rows)
+}
+
+func (c *csvStream) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// tsvStream prints tab separated values, tabs and newlines within values are replaced by spaces
+type tsvStream struct{}
+
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func newTSVStream(d OutputData, _ string, _ [][]string) (PageWriter, error) {
+	t := &tsvStream{}
+	return t, t.WritePage([][]string{d.Headers}, nil)
+}
+
+func (t *tsvStream) WritePage(rows [][]string, _ []any) error {
+	var b strings.Builder
+	for _, row := range rows {
+		for i, val := range row {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(tsvReplacer.Replace(val))
+		}
+		b.WriteByte('\n')
+	}
+	_, err := fmt.Print(b.String())
+	return err
+}
+
+func (t *tsvStream) Close() error {
+	return nil
+}
+
+type textStream struct {
+	data OutputData
+}
+
+func newTextStream(d OutputData, _ string, _ [][]string) (PageWriter, error) {
+	if d.Title != "" {
+		fmt.Println(TitleStyle(CurrentTheme()).Render(d.Title))
+	}
+	return &textStream{data: d}, nil
+}
+
+func (t *textStream) WritePage(rows [][]string, _ []any) error {
+	for _, row := range rows {
+		t.data.printTextRow(row)
+	}
+	return nil
+}
+
+func (t *textStream) Close() error {
+	return nil
+}
+
+// sanitizeRow removes emojis that mess up table alignment
+func sanitizeRow(row []string) []string {
+	sanitized := make([]string, len(row))
+	for i, val := range row {
+		sanitized[i] = gomoji.RemoveEmojis(val)
+	}
+	return sanitized
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,9 +8,9 @@ import (
 
 	"github.com/TylerBrock/colorjson"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/lovelydeng/gomoji"
 	tint "github.com/lrstanley/bubbletint"
 	"github.com/olekukonko/tablewriter"
+	"github.com/ygelfand/plexctl/internal/clierr"
 	"github.com/ygelfand/plexctl/internal/config"
 	"gopkg.in/yaml.v3"
 )
@@ -51,14 +50,21 @@ var PlexctlTheme = &PlexctlTint{}
 
 // CurrentTheme returns the theme currently configured in config.Get()
 func CurrentTheme() tint.Tint {
-	cfg := config.Get()
+	if t, ok := FindTheme(config.Get().Theme); ok {
+		return t
+	}
+	return PlexctlTheme
+}
+
+// FindTheme looks up a built-in theme by its ID
+func FindTheme(id string) (tint.Tint, bool) {
 	tints := append([]tint.Tint{PlexctlTheme}, tint.DefaultTints()...)
 	for _, t := range tints {
-		if t.ID() == cfg.Theme {
-			return t
+		if t.ID() == id {
+			return t, true
 		}
 	}
-	return PlexctlTheme
+	return nil, false
 }
 
 // Accent returns the primary accent color for the theme (Plex Orange for our theme, or Cyan/Blue for others)
@@ -110,9 +116,14 @@ func SuccessStyle(t tint.Tint) lipgloss.Style {
 		Bold(true)
 }
 
-// RenderError prints a styled error message
+// RenderError prints a styled error message, or a json object for formats read by scripts
 func RenderError(err error) {
-	fmt.Fprintf(os.Stderr, "%s %v\n", ErrorStyle(CurrentTheme()).Render("Error:"), err)
+	if JSONErrors() {
+		b, _ := json.Marshal(map[string]clierr.Report{"error": clierr.NewReport(err)})
+		fmt.Fprintln(os.Stderr, string(b))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s %s\n", ErrorStyle(CurrentTheme()).Render("Error:"), clierr.Message(err))
 }
 
 // RenderSuccess prints a styled success message
@@ -128,30 +139,6 @@ type OutputData struct {
 	Raw     interface{} // Used for JSON/YAML
 }
 
-// Print handles the output based on the configured format
-func (d OutputData) Print() error {
-	cfg := config.Get()
-	// Robustly handle potentially quoted format strings from config
-	format := strings.Trim(strings.ToLower(cfg.OutputFormat), "\"")
-
-	switch format {
-	case "json":
-		return d.printJSON()
-	case "json-pretty":
-		return d.printJSONPretty()
-	case "yaml":
-		return d.printYAML()
-	case "csv":
-		return d.printCSV()
-	case "txt", "text":
-		return d.printText()
-	case "table":
-		fallthrough
-	default:
-		return d.printTable()
-	}
-}
-
 func (d OutputData) printJSONPretty() error {
 	rawJSON, err := json.Marshal(d.Raw)
 	if err != nil {
@@ -181,41 +168,43 @@ func (d OutputData) printJSON() error {
 	return nil
 }
 
-func (d OutputData) printYAML() error {
-	b, err := yaml.Marshal(d.Raw)
+// printNDJSON prints every element of a list on its own line, anything else as a single line
+func (d OutputData) printNDJSON() error {
+	data, err := d.genericRaw()
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(b))
+	items, ok := data.([]any)
+	if !ok {
+		items = []any{data}
+	}
+	for _, item := range items {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	}
 	return nil
 }
 
-func (d OutputData) printCSV() error {
-	w := csv.NewWriter(os.Stdout)
-	if err := w.Write(d.Headers); err != nil {
-		return err
-	}
-	if err := w.WriteAll(d.Rows); err != nil {
+func (d OutputData) printYAML() error {
+	b, err := yaml.Marshal(d.Raw)
+	if err != nil {
 		return err
 	}
-	w.Flush()
+	fmt.Println(string(b))
 	return nil
 }
 
-func (d OutputData) printText() error {
+func (d OutputData) printTextRow(row []string) {
 	theme := CurrentTheme()
-	if d.Title != "" {
-		fmt.Println(TitleStyle(theme).Render(d.Title))
-	}
-	for _, row := range d.Rows {
-		for i, val := range row {
-			if i < len(d.Headers) {
-				fmt.Printf("%s %s\n", LabelStyle(theme).Render(d.Headers[i]+":"), ValueStyle(theme).Render(val))
-			}
+	for i, val := range row {
+		if i < len(d.Headers) {
+			fmt.Printf("%s %s\n", LabelStyle(theme).Render(d.Headers[i]+":"), ValueStyle(theme).Render(val))
 		}
-		fmt.Println()
 	}
-	return nil
+	fmt.Println()
 }
 
 func (d OutputData) printTable() error {
@@ -226,13 +215,9 @@ func (d OutputData) printTable() error {
 	table := tablewriter.NewWriter(os.Stdout)
 	table.Header(d.Headers)
 
-	// Sanitize rows to remove emojis that mess up alignment
 	sanitizedRows := make([][]string, len(d.Rows))
 	for i, row := range d.Rows {
-		sanitizedRows[i] = make([]string, len(row))
-		for j, val := range row {
-			sanitizedRows[i][j] = gomoji.RemoveEmojis(val)
-		}
+		sanitizedRows[i] = sanitizeRow(row)
 	}
 
 	table.Bulk(sanitizedRows)
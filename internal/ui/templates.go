@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/ohler55/ojg/jp"
+)
+
+func parseGoTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("go-template output needs a template, e.g. -o go-template='{{range .}}{{.title}}{{\"\\n\"}}{{end}}'")
+	}
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// parseJSONPath accepts RFC 9535 paths ($.title) as well as the kubectl style {.title} and .title.
+// Paths are evaluated with ojg rather than the pb33f/jsonpath the code generator pulls in, as every
+// release of that one builds on a release candidate of yaml v4.
+func parseJSONPath(expr string) (jp.Expr, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	if expr == "" {
+		return nil, fmt.Errorf("jsonpath output needs a path, e.g. -o jsonpath='$[*].title'")
+	}
+	if !strings.HasPrefix(expr, "$") {
+		if !strings.HasPrefix(expr, ".") && !strings.HasPrefix(expr, "[") {
+			expr = "." + expr
+		}
+		expr = "$" + expr
+	}
+	path, err := jp.ParseString(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonpath '%s': %w", expr, err)
+	}
+	return path, nil
+}
+
+type customColumn struct {
+	header string
+	path   jp.Expr
+}
+
+// parseCustomColumns parses HEADER:path pairs separated by commas, e.g. TITLE:.title,YEAR:.year
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns output needs columns, e.g. -o custom-columns=TITLE:.title,YEAR:.year")
+	}
+	var columns []customColumn
+	for _, part := range strings.Split(spec, ",") {
+		header, expr, ok := strings.Cut(part, ":")
+		if !ok || header == "" {
+			return nil, fmt.Errorf("invalid custom column '%s', expected HEADER:path", part)
+		}
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: header, path: path})
+	}
+	return columns, nil
+}
+
+// genericRaw converts the raw data to plain maps and slices through its JSON form, so templates
+// and paths use the same field names as -o json
+func (d OutputData) genericRaw() (any, error) {
+	b, err := json.Marshal(d.Raw)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (d OutputData) printGoTemplate(text string) error {
+	tmpl, err := parseGoTemplate(text)
+	if err != nil {
+		return err
+	}
+	data, err := d.genericRaw()
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(os.Stdout, data)
+}
+
+// printJSONPath prints every match on its own line, scalars as is and anything else as JSON
+func (d OutputData) printJSONPath(expr string) error {
+	path, err := parseJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	root, err := d.genericRaw()
+	if err != nil {
+		return err
+	}
+	for _, value := range path.Get(root) {
+		s, err := valueString(value)
+		if err != nil {
+			return err
+		}
+		fmt.Println(s)
+	}
+	return nil
+}
+
+// printCustomColumns evaluates the columns against every element of the raw data, or the raw data itself
+// when it is not a list
+func (d OutputData) printCustomColumns(spec string) error {
+	columns, err := parseCustomColumns(spec)
+	if err != nil {
+		return err
+	}
+	root, err := d.genericRaw()
+	if err != nil {
+		return err
+	}
+
+	elements := []any{root}
+	if list, ok := root.([]any); ok {
+		elements = list
+	}
+
+	out := OutputData{}
+	for _, c := range columns {
+		out.Headers = append(out.Headers, c.header)
+	}
+	for _, el := range elements {
+		var row []string
+		for _, c := range columns {
+			var values []string
+			for _, value := range c.path.Get(el) {
+				s, err := valueString(value)
+				if err != nil {
+					return err
+				}
+				values = append(values, s)
+			}
+			if len(values) == 0 {
+				values = []string{"<none>"}
+			}
+			row = append(row, strings.Join(values, ","))
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return out.printTable()
+}
+
+// valueString prints strings as is and anything else as JSON
+func valueString(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
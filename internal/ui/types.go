@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"slices"
+
 	"github.com/LukeHagar/plexgo/models/components"
 	"github.com/LukeHagar/plexgo/models/operations"
 	tea "github.com/charmbracelet/bubbletea"
@@ -53,6 +55,25 @@ type MediaPageMsg struct {
 	Total    int
 }
 
+// MediaChangedMsg is broadcast to every tab after server notifications invalidated cached data
+type MediaChangedMsg struct {
+	Sections []string // sections whose contents changed and should be reloaded
+	Items    []string // rating keys whose state changed, including parents of changed items
+	Sessions bool
+}
+
+func (m MediaChangedMsg) HasSection(sectionID string) bool {
+	return slices.Contains(m.Sections, sectionID)
+}
+
+func (m MediaChangedMsg) HasItem(ratingKey string) bool {
+	return slices.Contains(m.Items, ratingKey)
+}
+
+type RatingKeyProvider interface {
+	GetRatingKey() string
+}
+
 type RootChecker interface {
 	IsAtRoot() bool
 }
@@ -94,3 +115,10 @@ func PtrToString(p *string) string {
 	}
 	return *p
 }
+
+func PtrToInt(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
@@ -0,0 +1,261 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Mapping translates a local media folder to the path the server sees it under
+type Mapping struct {
+	Local  string
+	Server string
+}
+
+// ParseMapping parses a local=server mapping
+func ParseMapping(s string) (Mapping, error) {
+	local, server, ok := strings.Cut(s, "=")
+	if !ok || local == "" || server == "" {
+		return Mapping{}, fmt.Errorf("invalid mapping '%s', expected /local/path=/server/path", s)
+	}
+	abs, err := filepath.Abs(local)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("invalid local path '%s': %w", local, err)
+	}
+	return Mapping{Local: abs, Server: path.Clean(server)}, nil
+}
+
+// Section is a library section and the server folders it covers
+type Section struct {
+	ID        int64
+	Title     string
+	Locations []string
+}
+
+// ScanFunc triggers a scan of a single server folder in a section
+type ScanFunc func(ctx context.Context, section Section, serverPath string) error
+
+// Options tune how changes are batched and acted on
+type Options struct {
+	Debounce time.Duration // quiet period after the last change before scanning
+	DryRun   bool          // log the scans that would be issued without issuing them
+}
+
+// Watcher scans the matching library folder when files under the mapped paths change
+type Watcher struct {
+	mappings []Mapping
+	sections []Section
+	opts     Options
+	scan     ScanFunc
+
+	fs      *fsnotify.Watcher
+	pending map[string]bool
+}
+
+// New creates a watcher for the given mappings. Run starts watching.
+func New(mappings []Mapping, sections []Section, opts Options, scan ScanFunc) *Watcher {
+	return &Watcher{
+		mappings: mappings,
+		sections: sections,
+		opts:     opts,
+		scan:     scan,
+		pending:  make(map[string]bool),
+	}
+}
+
+// Run watches the mapped folders until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	var err error
+	w.fs, err = fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer w.fs.Close()
+
+	for _, m := range w.mappings {
+		if err := w.addTree(m.Local); err != nil {
+			return err
+		}
+		if len(w.sectionsUnder(m.Server)) == 0 {
+			slog.Warn("Watch: No library section covers mapped path", "local", m.Local, "server", m.Server)
+		}
+		slog.Info("Watch: Watching", "local", m.Local, "server", m.Server)
+	}
+
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("Watch: Watcher error", "error", err)
+		case e, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			if w.handle(e) {
+				timer.Reset(w.opts.Debounce)
+			}
+		case <-timer.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+// handle records the folder affected by an event and reports whether anything is pending
+func (w *Watcher) handle(e fsnotify.Event) bool {
+	if e.Op == fsnotify.Chmod || strings.HasPrefix(filepath.Base(e.Name), ".") {
+		return false
+	}
+	slog.Debug("Watch: Change", "path", e.Name, "op", e.Op.String())
+
+	dir := filepath.Dir(e.Name)
+	if e.Has(fsnotify.Create) {
+		if err := w.addTree(e.Name); err == nil && isDir(e.Name) {
+			// A new folder, e.g. a freshly moved in season, is scanned on its own
+			dir = e.Name
+		}
+	}
+	w.pending[dir] = true
+	return true
+}
+
+// flush scans the folders changed since the last flush, skipping ones inside another pending folder
+func (w *Watcher) flush(ctx context.Context) {
+	dirs := make([]string, 0, len(w.pending))
+	for d := range w.pending {
+		dirs = append(dirs, d)
+	}
+	w.pending = make(map[string]bool)
+	sort.Strings(dirs)
+
+	var scanned []string
+	for _, dir := range dirs {
+		if covered(dir, scanned, filepath.Separator) {
+			continue
+		}
+		scanned = append(scanned, dir)
+
+		serverPath, ok := w.toServer(dir)
+		if !ok {
+			continue
+		}
+		section, ok := w.sectionFor(serverPath)
+		if !ok {
+			slog.Warn("Watch: No library section for changed folder", "local", dir, "server", serverPath)
+			continue
+		}
+
+		if w.opts.DryRun {
+			slog.Info("Watch: Would scan", "section", section.Title, "section_id", section.ID, "path", serverPath)
+			continue
+		}
+		slog.Info("Watch: Scanning", "section", section.Title, "section_id", section.ID, "path", serverPath)
+		if err := w.scan(ctx, section, serverPath); err != nil {
+			slog.Error("Watch: Scan failed", "section_id", section.ID, "path", serverPath, "error", err)
+		}
+	}
+}
+
+// addTree watches root and every folder below it
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return fmt.Errorf("failed to watch %s: %w", root, err)
+			}
+			slog.Warn("Watch: Skipping unreadable folder", "path", p, "error", err)
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if err := w.fs.Add(p); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", p, err)
+		}
+		return nil
+	})
+}
+
+// toServer translates a local folder to the server's path using the most specific mapping
+func (w *Watcher) toServer(local string) (string, bool) {
+	var best *Mapping
+	for i, m := range w.mappings {
+		if within(local, m.Local, filepath.Separator) && (best == nil || len(m.Local) > len(best.Local)) {
+			best = &w.mappings[i]
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(best.Local, local)
+	if err != nil {
+		return "", false
+	}
+	return path.Join(best.Server, filepath.ToSlash(rel)), true
+}
+
+// sectionFor returns the section whose location most specifically contains serverPath
+func (w *Watcher) sectionFor(serverPath string) (Section, bool) {
+	var best Section
+	bestLen := -1
+	for _, s := range w.sections {
+		for _, loc := range s.Locations {
+			if within(serverPath, loc, '/') && len(loc) > bestLen {
+				best, bestLen = s, len(loc)
+			}
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// sectionsUnder returns the sections with a location inside or containing serverPath
+func (w *Watcher) sectionsUnder(serverPath string) []Section {
+	var res []Section
+	for _, s := range w.sections {
+		for _, loc := range s.Locations {
+			if within(serverPath, loc, '/') || within(loc, serverPath, '/') {
+				res = append(res, s)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func within(p, root string, sep rune) bool {
+	root = strings.TrimRight(root, string(sep))
+	return p == root || strings.HasPrefix(p, root+string(sep))
+}
+
+func covered(p string, roots []string, sep rune) bool {
+	for _, r := range roots {
+		if within(p, r, sep) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDir(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.IsDir()
+}